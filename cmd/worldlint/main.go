@@ -0,0 +1,38 @@
+// Command worldlint checks the world data for broken references and exits non-zero when it finds errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Jasrags/NewMUD/internal/game"
+	"github.com/Jasrags/NewMUD/pluralizer"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	strict := flag.Bool("strict", false, "treat warnings as errors")
+	flag.Parse()
+
+	gs := game.NewGameServer()
+	gs.SetupConfig()
+
+	// Only the problems found by the lint are of interest
+	viper.Set("server.log_level", "error")
+	gs.SetupLogger()
+
+	game.PermissionMgr.LoadDataFiles()
+	game.EntityMgr.LoadDataFiles()
+
+	issues := game.EntityMgr.LintWorld(os.DirFS(viper.GetString("data.areas_path")))
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	fmt.Printf("%s found\n", pluralizer.PluralizeNounPhrase("issue", len(issues)))
+
+	if game.HasLintErrors(issues) || (*strict && len(issues) > 0) {
+		os.Exit(1)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gliderlabs/ssh"
 	"github.com/spf13/viper"
 )
 
@@ -53,6 +54,33 @@ func (mgr *AccountManager) GetByUsername(username string) *Account {
 
 	return mgr.accounts[strings.ToLower(username)]
 }
+
+// GetByPublicKey returns the account for username if the key is registered with it.
+func (mgr *AccountManager) GetByPublicKey(username string, key ssh.PublicKey) *Account {
+	u := mgr.GetByUsername(username)
+	if u == nil || !u.HasPublicKey(key) {
+		return nil
+	}
+
+	return u
+}
+
+// GetByCharacterName returns the account that owns the named character.
+func (mgr *AccountManager) GetByCharacterName(name string) *Account {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	for _, u := range mgr.accounts {
+		for _, charName := range u.Characters {
+			if strings.EqualFold(charName, name) {
+				return u
+			}
+		}
+	}
+
+	return nil
+}
+
 func (mgr *AccountManager) RemoveAccount(u *Account) {
 	mgr.Lock()
 	defer mgr.Unlock()
@@ -104,6 +132,57 @@ func (mgr *AccountManager) GetAccountNames() []string {
 	return names
 }
 
+// PurgeDeletedAccounts permanently removes accounts whose deletion grace period has passed along with
+// their characters, freeing up the account and character names.
+func (mgr *AccountManager) PurgeDeletedAccounts() {
+	mgr.RLock()
+	var expired []*Account
+	for _, u := range mgr.accounts {
+		if u.IsDeleted() && time.Now().After(u.PurgeAt()) {
+			expired = append(expired, u)
+		}
+	}
+	mgr.RUnlock()
+
+	for _, u := range expired {
+		slog.Info("Purging deleted user",
+			slog.String("id", u.ID),
+			slog.String("username", u.Username))
+
+		for _, name := range u.Characters {
+			if c := CharacterMgr.GetCharacterByName(name); c != nil {
+				CharacterMgr.RemoveCharacter(c)
+			}
+
+			filePath := filepath.Join(viper.GetString("data.characters_path"), strings.ToLower(name)+".yml")
+			if err := RemoveFile(filePath); err != nil {
+				slog.Error("failed to remove character file",
+					slog.String("file", filePath),
+					slog.Any("error", err))
+			}
+		}
+
+		filePath := filepath.Join(viper.GetString("data.accounts_path"), strings.ToLower(u.Username)+".yml")
+		if err := RemoveFile(filePath); err != nil {
+			slog.Error("failed to remove user file",
+				slog.String("file", filePath),
+				slog.Any("error", err))
+		}
+
+		mgr.RemoveAccount(u)
+	}
+}
+
+// StartPurger periodically purges expired deleted accounts.
+func (mgr *AccountManager) StartPurger(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		mgr.PurgeDeletedAccounts()
+	}
+}
+
 func (mgr *AccountManager) LoadDataFiles() {
 	dataFilePath := viper.GetString("data.accounts_path")
 	bannedNames := viper.GetStringSlice("banned_names")
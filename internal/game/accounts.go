@@ -1,31 +1,46 @@
 package game
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
 	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/gliderlabs/ssh"
 	"github.com/google/uuid"
 	"github.com/spf13/viper"
 	ee "github.com/vansante/go-event-emitter"
 	"golang.org/x/crypto/bcrypt"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 type Account struct {
 	sync.RWMutex `yaml:"-"`
 	Listeners    []ee.Listener `yaml:"-"`
 
-	ID          string     `yaml:"id"`
-	Username    string     `yaml:"username"`
-	Password    string     `yaml:"password"`
-	Characters  []string   `yaml:"characters"`
-	CreatedAt   time.Time  `yaml:"created_at"`
-	UpdatedAt   *time.Time `yaml:"updated_at"`
-	LastLoginAt *time.Time `yaml:"last_login_at"`
-	DeletedAt   *time.Time `yaml:"deleted_at"`
-	State       string     `yaml:"-"`
+	ID          string         `yaml:"id"`
+	Username    string         `yaml:"username"`
+	Password    string         `yaml:"password"`
+	Characters  []string       `yaml:"characters"`
+	PublicKeys  []string       `yaml:"public_keys,omitempty"`
+	Email       string         `yaml:"email,omitempty"`
+	Ignored     []string       `yaml:"ignored,omitempty"`
+	Mail        []*MailMessage `yaml:"mail,omitempty"`
+	CreatedAt   time.Time      `yaml:"created_at"`
+	UpdatedAt   *time.Time     `yaml:"updated_at"`
+	LastLoginAt *time.Time     `yaml:"last_login_at"`
+	DeletedAt   *time.Time     `yaml:"deleted_at"`
+	State       string         `yaml:"-"`
+
+	ResetTokenHash      string     `yaml:"reset_token_hash,omitempty"`
+	ResetTokenExpiresAt *time.Time `yaml:"reset_token_expires_at,omitempty"`
 }
 
 func NewAccount() *Account {
@@ -107,6 +122,292 @@ func (u *Account) CheckPassword(password string) bool {
 	return true
 }
 
+// GeneratePasswordResetToken creates a new single use reset token, storing only its hash on the account.
+func (u *Account) GeneratePasswordResetToken(ttl time.Duration) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	u.Lock()
+	defer u.Unlock()
+
+	slog.Debug("Generating password reset token",
+		slog.String("id", u.ID),
+		slog.String("username", u.Username))
+
+	sum := sha256.Sum256([]byte(token))
+	expiresAt := time.Now().Add(ttl)
+	u.ResetTokenHash = hex.EncodeToString(sum[:])
+	u.ResetTokenExpiresAt = &expiresAt
+
+	return token, nil
+}
+
+// CheckPasswordResetToken reports whether token matches the account's unexpired reset token.
+func (u *Account) CheckPasswordResetToken(token string) bool {
+	u.RLock()
+	defer u.RUnlock()
+
+	if u.ResetTokenHash == "" || u.ResetTokenExpiresAt == nil || time.Now().After(*u.ResetTokenExpiresAt) {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(u.ResetTokenHash)) == 1
+}
+
+func (u *Account) ClearPasswordResetToken() {
+	u.Lock()
+	defer u.Unlock()
+
+	u.ResetTokenHash = ""
+	u.ResetTokenExpiresAt = nil
+}
+
+// MarkDeleted soft deletes the account, it is purged once the grace period has passed.
+func (u *Account) MarkDeleted() {
+	u.Lock()
+	defer u.Unlock()
+
+	slog.Info("Marking user deleted",
+		slog.String("id", u.ID),
+		slog.String("username", u.Username))
+
+	t := time.Now()
+	u.DeletedAt = &t
+}
+
+// Restore cancels a pending deletion.
+func (u *Account) Restore() {
+	u.Lock()
+	defer u.Unlock()
+
+	slog.Info("Restoring deleted user",
+		slog.String("id", u.ID),
+		slog.String("username", u.Username))
+
+	u.DeletedAt = nil
+}
+
+func (u *Account) IsDeleted() bool {
+	u.RLock()
+	defer u.RUnlock()
+
+	return u.DeletedAt != nil
+}
+
+// PurgeAt returns when a soft deleted account is due to be purged.
+func (u *Account) PurgeAt() time.Time {
+	u.RLock()
+	defer u.RUnlock()
+
+	if u.DeletedAt == nil {
+		return time.Time{}
+	}
+
+	return u.DeletedAt.Add(viper.GetDuration("server.account_deletion_grace_period"))
+}
+
+// Ignore adds the character name to the account's ignore list.
+func (u *Account) Ignore(name string) bool {
+	u.Lock()
+	defer u.Unlock()
+
+	name = strings.ToLower(name)
+	if slices.Contains(u.Ignored, name) {
+		return false
+	}
+
+	u.Ignored = append(u.Ignored, name)
+
+	return true
+}
+
+// Unignore removes the character name from the account's ignore list.
+func (u *Account) Unignore(name string) bool {
+	u.Lock()
+	defer u.Unlock()
+
+	name = strings.ToLower(name)
+	if !slices.Contains(u.Ignored, name) {
+		return false
+	}
+
+	u.Ignored = slices.DeleteFunc(u.Ignored, func(n string) bool { return n == name })
+
+	return true
+}
+
+// IsIgnoring reports whether the sender, or another character on the same account as an ignored
+// character, is on the ignore list.
+func (u *Account) IsIgnoring(sender *Character) bool {
+	u.RLock()
+	defer u.RUnlock()
+
+	senderName := strings.ToLower(sender.Name)
+	for _, name := range u.Ignored {
+		if name == senderName {
+			return true
+		}
+
+		if sender.AccountID == "" {
+			continue
+		}
+
+		if ignored := CharacterMgr.GetCharacterByName(name); ignored != nil && ignored.AccountID == sender.AccountID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddMail adds a message to the account's mailbox.
+func (u *Account) AddMail(msg *MailMessage) {
+	u.Lock()
+	defer u.Unlock()
+
+	u.Mail = append(u.Mail, msg)
+}
+
+// GetMail returns the message at the index in the mailbox, or nil if there isn't one.
+func (u *Account) GetMail(index int) *MailMessage {
+	u.RLock()
+	defer u.RUnlock()
+
+	if index < 0 || index >= len(u.Mail) {
+		return nil
+	}
+
+	return u.Mail[index]
+}
+
+// DeleteMail removes the message at the index from the mailbox.
+func (u *Account) DeleteMail(index int) error {
+	u.Lock()
+	defer u.Unlock()
+
+	if index < 0 || index >= len(u.Mail) {
+		return fmt.Errorf("invalid message number %d", index+1)
+	}
+
+	u.Mail = slices.Delete(u.Mail, index, index+1)
+
+	return nil
+}
+
+// UnreadMailCount returns the number of messages in the mailbox that haven't been read.
+func (u *Account) UnreadMailCount() int {
+	u.RLock()
+	defer u.RUnlock()
+
+	count := 0
+	for _, msg := range u.Mail {
+		if !msg.Read {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AddPublicKey parses an authorized_keys formatted line and registers the key with the account.
+func (u *Account) AddPublicKey(line string) (ssh.PublicKey, error) {
+	key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(line)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	if u.HasPublicKey(key) {
+		return nil, fmt.Errorf("public key is already registered")
+	}
+
+	u.Lock()
+	defer u.Unlock()
+
+	slog.Debug("Adding public key to user",
+		slog.String("user_id", u.ID),
+		slog.String("fingerprint", gossh.FingerprintSHA256(key)))
+
+	entry := strings.TrimSpace(string(gossh.MarshalAuthorizedKey(key)))
+	if comment != "" {
+		entry += " " + comment
+	}
+	u.PublicKeys = append(u.PublicKeys, entry)
+
+	return key, nil
+}
+
+// RemovePublicKey removes the registered key at the given index.
+func (u *Account) RemovePublicKey(index int) error {
+	u.Lock()
+	defer u.Unlock()
+
+	if index < 0 || index >= len(u.PublicKeys) {
+		return fmt.Errorf("invalid key index %d", index+1)
+	}
+
+	slog.Debug("Removing public key from user",
+		slog.String("user_id", u.ID),
+		slog.Int("index", index))
+
+	u.PublicKeys = append(u.PublicKeys[:index], u.PublicKeys[index+1:]...)
+
+	return nil
+}
+
+// HasPublicKey reports whether the key is registered with the account.
+func (u *Account) HasPublicKey(key ssh.PublicKey) bool {
+	if key == nil {
+		return false
+	}
+
+	u.RLock()
+	defer u.RUnlock()
+
+	for _, line := range u.PublicKeys {
+		registered, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			slog.Warn("Invalid public key on user",
+				slog.String("user_id", u.ID),
+				slog.Any("error", err))
+			continue
+		}
+
+		if ssh.KeysEqual(registered, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PublicKeyFingerprints returns the SHA256 fingerprints of the registered keys.
+func (u *Account) PublicKeyFingerprints() []string {
+	u.RLock()
+	defer u.RUnlock()
+
+	var fingerprints []string
+	for _, line := range u.PublicKeys {
+		key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			fingerprints = append(fingerprints, "invalid key")
+			continue
+		}
+
+		fingerprint := key.Type() + " " + gossh.FingerprintSHA256(key)
+		if comment != "" {
+			fingerprint += " " + comment
+		}
+		fingerprints = append(fingerprints, fingerprint)
+	}
+
+	return fingerprints
+}
+
 func (u *Account) Save() error {
 	u.Lock()
 	defer u.Unlock()
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPublicKey      = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl test@example"
+	testOtherPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB6VAT4aAHxgPMoFJv6BHyA5rbtXJgp+r58y/Qm7Wc6S other@example"
+)
+
+func TestAccountPublicKeys(t *testing.T) {
+	a := NewAccount()
+
+	key, err := a.AddPublicKey(testPublicKey)
+	assert.NoError(t, err)
+	assert.True(t, a.HasPublicKey(key))
+
+	_, err = a.AddPublicKey(testPublicKey)
+	assert.Error(t, err, "duplicate keys should be rejected")
+
+	_, err = a.AddPublicKey("not a key")
+	assert.Error(t, err)
+
+	other, _, _, _, err := ssh.ParseAuthorizedKey([]byte(testOtherPublicKey))
+	assert.NoError(t, err)
+	assert.False(t, a.HasPublicKey(other))
+	assert.False(t, a.HasPublicKey(nil))
+
+	fingerprints := a.PublicKeyFingerprints()
+	assert.Len(t, fingerprints, 1)
+	assert.Contains(t, fingerprints[0], "test@example")
+
+	assert.Error(t, a.RemovePublicKey(1))
+	assert.NoError(t, a.RemovePublicKey(0))
+	assert.False(t, a.HasPublicKey(key))
+}
+
+func TestAccountPasswordResetToken(t *testing.T) {
+	a := NewAccount()
+	assert.False(t, a.CheckPasswordResetToken(""), "no token issued yet")
+
+	token, err := a.GeneratePasswordResetToken(time.Minute)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, a.ResetTokenHash, "only the hash should be stored")
+	assert.False(t, a.CheckPasswordResetToken("wrong"))
+	assert.True(t, a.CheckPasswordResetToken(token))
+
+	a.ClearPasswordResetToken()
+	assert.False(t, a.CheckPasswordResetToken(token))
+
+	token, err = a.GeneratePasswordResetToken(-time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, a.CheckPasswordResetToken(token), "expired tokens should be rejected")
+}
+
+func TestAccountIgnore(t *testing.T) {
+	a := NewAccount()
+	bob := newTestCharacter("Bob")
+	bob.AccountID = "bob-account"
+	alt := newTestCharacter("Bobalt")
+	alt.AccountID = "bob-account"
+	carol := newTestCharacter("Carol")
+	CharacterMgr.AddCharacter(bob)
+	defer CharacterMgr.RemoveCharacter(bob)
+
+	assert.True(t, a.Ignore("Bob"))
+	assert.False(t, a.Ignore("bob"), "names are case insensitive")
+	assert.True(t, a.IsIgnoring(bob))
+	assert.True(t, a.IsIgnoring(alt), "other characters on the same account are ignored")
+	assert.False(t, a.IsIgnoring(carol))
+
+	assert.True(t, a.Unignore("BOB"))
+	assert.False(t, a.Unignore("bob"))
+	assert.False(t, a.IsIgnoring(alt))
+}
+
+func TestAccountMail(t *testing.T) {
+	a := NewAccount()
+	a.AddMail(&MailMessage{From: "Alice", Subject: "one"})
+	a.AddMail(&MailMessage{From: "Bob", Subject: "two", Read: true})
+
+	assert.Equal(t, 1, a.UnreadMailCount())
+	assert.Equal(t, "two", a.GetMail(1).Subject)
+	assert.Nil(t, a.GetMail(2))
+
+	assert.Error(t, a.DeleteMail(-1))
+	assert.NoError(t, a.DeleteMail(0))
+	assert.Equal(t, 0, a.UnreadMailCount())
+	assert.Len(t, a.Mail, 1)
+}
@@ -2,10 +2,17 @@ package game
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Jasrags/NewMUD/pluralizer"
 	"github.com/gliderlabs/ssh"
 	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/spf13/viper"
 )
 
 func DoSpawn(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
@@ -20,30 +27,38 @@ func DoSpawn(s ssh.Session, cmd string, args []string, user *Account, char *Char
 	switch entityType {
 	case "i":
 		// Spawn an item into the character inventory
-		item := EntityMgr.CreateItemInstanceFromBlueprintID(entityName)
+		var item *ItemInstance
+		if bp := EntityMgr.ResolveItemBlueprint(room.AreaID, entityName); bp != nil {
+			item = EntityMgr.CreateItemInstanceFromBlueprint(bp)
+		}
 		if item == nil {
 			WriteStringF(s, "{{Error: No item blueprint named '%s' found.}}::red"+CRLF, entityName)
 			return
 		}
 
 		char.Inventory.Add(item)
+		AuditMgr.LogEntities(char, AuditTypeEntityCreated, cmd, []string{item.BlueprintID + "#" + item.InstanceID}, room)
 
 		WriteStringF(s, "{{You spawn a %s.}}::green"+CRLF, item.Blueprint.Name)
-		room.Broadcast(cfmt.Sprintf("{{%s spawns a %s.}}::green"+CRLF, char.Name, item.Blueprint.Name), []string{char.ID})
+		room.BroadcastAbout(char, cfmt.Sprintf("{{%s spawns a %s.}}::green"+CRLF, char.Name, item.Blueprint.Name), []string{char.ID})
 		char.Save()
 
 	case "m":
 		// Spawn a mob into the room
-		mob := EntityMgr.CreateMobInstanceFromBlueprintID(entityName)
+		var mob *MobInstance
+		if bp := EntityMgr.ResolveMobBlueprint(room.AreaID, entityName); bp != nil {
+			mob = EntityMgr.CreateMobInstanceFromBlueprint(bp)
+		}
 		if mob == nil {
 			WriteStringF(s, "{{Error: No mob blueprint named '%s' found.}}::red"+CRLF, entityName)
 			return
 		}
 
 		room.AddMobInstance(mob)
+		AuditMgr.LogEntities(char, AuditTypeEntityCreated, cmd, []string{mob.BlueprintID + "#" + mob.InstanceID}, room)
 
 		WriteStringF(s, "{{You spawn a mob named %s.}}::green"+CRLF, entityName)
-		room.Broadcast(cfmt.Sprintf("{{%s spawns a mob named %s.}}::green"+CRLF, char.Name, entityName), []string{char.ID})
+		room.BroadcastAbout(char, cfmt.Sprintf("{{%s spawns a mob named %s.}}::green"+CRLF, char.Name, entityName), []string{char.ID})
 
 	default:
 		WriteString(s, "{{Invalid entity type. Usage: spawn <item|mob> <name>}}::yellow"+CRLF)
@@ -109,7 +124,7 @@ func DoGoto(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	target := args[0]
 
 	// Check if the target is a room ID
-	newRoom := EntityMgr.GetRoom(target)
+	newRoom := EntityMgr.ResolveRoom(room.AreaID, target)
 	if newRoom != nil {
 		if char.Room == newRoom {
 			WriteString(s, "{{You are already in that room.}}::yellow"+CRLF)
@@ -146,7 +161,7 @@ func DoGoto(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 func DoList(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
 	// Validate arguments.
 	if len(args) < 1 {
-		WriteString(s, cfmt.Sprintf("{{Usage: list <mobs|items|rooms> [tags]}}::yellow"+CRLF))
+		WriteString(s, cfmt.Sprintf("{{Usage: list <areas|mobs|items|rooms> [tags]}}::yellow"+CRLF))
 		return
 	}
 
@@ -163,11 +178,21 @@ func DoList(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	var outputBuilder strings.Builder
 
 	switch category {
+	case "areas", "a":
+		for _, a := range EntityMgr.GetAllAreas() {
+			if HasAnyTag(a.Flags, filterTags) {
+				outputBuilder.WriteString(cfmt.Sprintf("ID: {{%-20s}}::white|bold  Title: {{%-20s}}::white|bold  Karma: {{%-8s}}::white|bold  Threat: {{%-9s}}::white|bold  Reset: {{%-6s}}::white|bold  Flags: {{%s}}::white|bold"+CRLF,
+					a.ID, a.Title, a.KarmaRange(), a.ThreatLevel, a.ResetInterval, strings.Join(a.Flags, ", ")))
+			}
+		}
+		if outputBuilder.Len() == 0 {
+			outputBuilder.WriteString(cfmt.Sprintf("{{No areas found with the specified flags.}}::red" + CRLF))
+		}
 	case "rooms", "r":
 		rooms := EntityMgr.GetAllRooms()
 		for _, r := range rooms {
-			if HasAnyTag(r.Tags, filterTags) {
-				outputBuilder.WriteString(cfmt.Sprintf("ID: {{%-35s}}::white|bold  Title: {{%-35s}}::white|bold  Tags: {{%s}}::white|bold"+CRLF, r.ID, r.Title, strings.Join(r.Tags, ", ")))
+			if tags := r.AllTags(); HasAnyTag(tags, filterTags) {
+				outputBuilder.WriteString(cfmt.Sprintf("ID: {{%-35s}}::white|bold  Title: {{%-35s}}::white|bold  Tags: {{%s}}::white|bold"+CRLF, r.QualifiedID(), r.Title, strings.Join(tags, ", ")))
 			}
 		}
 		if outputBuilder.Len() == 0 {
@@ -177,7 +202,7 @@ func DoList(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		items := EntityMgr.GetAllItemBlueprints()
 		for _, it := range items {
 			if HasAnyTag(it.Tags, filterTags) {
-				outputBuilder.WriteString(cfmt.Sprintf("ID: {{%-20s}}::white|bold  Name: {{%-20s}}::white|bold  Tags: {{%s}}::white|bold"+CRLF, it.ID, it.Name, strings.Join(it.Tags, ", ")))
+				outputBuilder.WriteString(cfmt.Sprintf("ID: {{%-20s}}::white|bold  Name: {{%-20s}}::white|bold  Tags: {{%s}}::white|bold"+CRLF, it.QualifiedID(), it.Name, strings.Join(it.Tags, ", ")))
 			}
 		}
 		if outputBuilder.Len() == 0 {
@@ -187,16 +212,324 @@ func DoList(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		mobs := EntityMgr.GetAllMobBlueprints()
 		for _, m := range mobs {
 			if HasAnyTag(m.Tags, filterTags) {
-				outputBuilder.WriteString(cfmt.Sprintf("ID: {{%-20s}}::white|bold  Name: {{%-20s}}::white|bold  Tags: {{%s}}::white|bold"+CRLF, m.ID, m.Name, strings.Join(m.Tags, ", ")))
+				outputBuilder.WriteString(cfmt.Sprintf("ID: {{%-20s}}::white|bold  Name: {{%-20s}}::white|bold  Tags: {{%s}}::white|bold"+CRLF, m.QualifiedID(), m.Name, strings.Join(m.Tags, ", ")))
 			}
 		}
 		if outputBuilder.Len() == 0 {
 			outputBuilder.WriteString(cfmt.Sprintf("{{No mobs found with the specified tags.}}::red" + CRLF))
 		}
 	default:
-		WriteString(s, cfmt.Sprintf("{{Unknown category '%s'. Valid categories are: mobs, items, rooms.}}::red"+CRLF, args[0]))
+		WriteString(s, cfmt.Sprintf("{{Unknown category '%s'. Valid categories are: areas, mobs, items, rooms.}}::red"+CRLF, args[0]))
 		return
 	}
 
 	WriteString(s, outputBuilder.String())
 }
+
+func DoGrant(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) != 2 {
+		WriteString(s, "{{Usage: grant <character> <permission>}}::yellow"+CRLF)
+		return
+	}
+
+	target := CharacterMgr.GetCharacterByName(args[0])
+	if target == nil {
+		WriteStringF(s, "{{Character '%s' not found.}}::red"+CRLF, args[0])
+		return
+	}
+
+	permission := strings.ToLower(args[1])
+	if msg := checkPermissionChange(char, target, permission); msg != "" {
+		WriteStringF(s, "{{%s}}::red"+CRLF, msg)
+		return
+	}
+
+	target.GrantPermission(permission)
+	target.Save()
+	AuditMgr.LogPermissionChange(char, cmd, target, permission)
+
+	WriteStringF(s, "{{You grant %s the '%s' permission.}}::green"+CRLF, target.Name, permission)
+	if target.Conn != nil && target != char {
+		target.Send(cfmt.Sprintf("{{You have been granted the '%s' permission.}}::green"+CRLF, permission))
+	}
+}
+
+func DoRevoke(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) != 2 {
+		WriteString(s, "{{Usage: revoke <character> <permission>}}::yellow"+CRLF)
+		return
+	}
+
+	target := CharacterMgr.GetCharacterByName(args[0])
+	if target == nil {
+		WriteStringF(s, "{{Character '%s' not found.}}::red"+CRLF, args[0])
+		return
+	}
+
+	permission := strings.ToLower(args[1])
+	if msg := checkPermissionChange(char, target, permission); msg != "" {
+		WriteStringF(s, "{{%s}}::red"+CRLF, msg)
+		return
+	}
+
+	target.RevokePermission(permission)
+	target.Save()
+	AuditMgr.LogPermissionChange(char, cmd, target, permission)
+
+	WriteStringF(s, "{{You revoke the '%s' permission from %s.}}::green"+CRLF, permission, target.Name)
+	if target.Conn != nil && target != char {
+		target.Send(cfmt.Sprintf("{{Your '%s' permission has been revoked.}}::yellow"+CRLF, permission))
+	}
+}
+
+// checkPermissionChange returns why the character can't grant or revoke the permission for the target, or
+// an empty string if they can. Only known permissions the character holds themselves can be changed, and
+// only for characters below their permission level.
+func checkPermissionChange(char, target *Character, permission string) string {
+	switch {
+	case !IsKnownPermission(permission):
+		return fmt.Sprintf("'%s' is not a known permission.", permission)
+	case !char.HasPermission(permission):
+		return fmt.Sprintf("You don't have the '%s' permission yourself.", permission)
+	case char.GetPermissionLevel() <= target.GetPermissionLevel():
+		return "You can only change the permissions of characters below your level."
+	}
+
+	return ""
+}
+
+func DoPermissions(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	// Without a target list the permission groups
+	if len(args) == 0 {
+		groups := PermissionMgr.GetGroups()
+		sorted := make([]*PermissionGroup, 0, len(groups))
+		for _, g := range groups {
+			sorted = append(sorted, g)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Level < sorted[j].Level
+		})
+
+		WriteString(s, "{{Permission groups:}}::white|bold"+CRLF)
+		for _, g := range sorted {
+			WriteStringF(s, "{{%-10s}}::%s {{level %3d}}::white {{%s}}::cyan"+CRLF, g.ID, g.Color, g.Level, strings.Join(g.Permissions, ", "))
+			if len(g.Inherits) > 0 {
+				WriteStringF(s, "           {{inherits: %s}}::white"+CRLF, strings.Join(g.Inherits, ", "))
+			}
+		}
+		return
+	}
+
+	target := CharacterMgr.GetCharacterByName(args[0])
+	if target == nil {
+		WriteStringF(s, "{{Character '%s' not found.}}::red"+CRLF, args[0])
+		return
+	}
+
+	WriteStringF(s, "{{Permissions for %s:}}::white|bold"+CRLF, target.Name)
+	WriteStringF(s, "{{Group:}}::white {{%s}}::cyan"+CRLF, target.Role)
+	WriteStringF(s, "{{Group permissions:}}::white {{%s}}::cyan"+CRLF, strings.Join(PermissionMgr.GetGroupPermissions(target.Role), ", "))
+	WriteStringF(s, "{{Granted:}}::white {{%s}}::green"+CRLF, strings.Join(target.Permissions, ", "))
+	WriteStringF(s, "{{Revoked:}}::white {{%s}}::red"+CRLF, strings.Join(target.Revoked, ", "))
+}
+
+func DoChatlog(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 || len(args) > 2 {
+		WriteString(s, "{{Usage: chatlog <character> [since]}}::yellow"+CRLF)
+		return
+	}
+
+	q := MessageLogQuery{Character: args[0]}
+	if len(args) == 2 {
+		since, err := ParseSince(args[1], time.Now())
+		if err != nil {
+			WriteStringF(s, "{{%s}}::red"+CRLF, err.Error())
+			return
+		}
+		q.Since = since
+	}
+
+	entries := MessageLogMgr.Query(q)
+	if len(entries) == 0 {
+		WriteStringF(s, "{{No messages found for '%s'.}}::yellow"+CRLF, args[0])
+		return
+	}
+
+	for _, e := range entries {
+		WriteStringF(s, "{{%s}}::white {{%-8s}}::yellow {{%-16s}}::cyan %s"+CRLF,
+			e.Time.Format("2006-01-02 15:04:05"), e.Type, e.RoomID, e.Format())
+	}
+}
+
+func DoAudit(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	var q AuditQuery
+
+	// Parse "<filter> <value>" pairs with an optional trailing limit
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "character", "char", "c":
+			if i+1 >= len(args) {
+				WriteString(s, "{{Usage: audit [character <name>] [type <type>] [limit]}}::yellow"+CRLF)
+				return
+			}
+			i++
+			q.Character = args[i]
+		case "type", "t":
+			if i+1 >= len(args) {
+				WriteString(s, "{{Usage: audit [character <name>] [type <type>] [limit]}}::yellow"+CRLF)
+				return
+			}
+			i++
+			if !slices.Contains(AuditTypes, strings.ToLower(args[i])) {
+				WriteStringF(s, "{{Unknown audit type '%s'. Valid types: %s}}::red"+CRLF, args[i], strings.Join(AuditTypes, ", "))
+				return
+			}
+			q.Type = args[i]
+		default:
+			limit, err := strconv.Atoi(args[i])
+			if err != nil || limit <= 0 {
+				WriteString(s, "{{Usage: audit [character <name>] [type <type>] [limit]}}::yellow"+CRLF)
+				return
+			}
+			q.Limit = limit
+		}
+	}
+
+	entries := AuditMgr.Query(q)
+	if len(entries) == 0 {
+		WriteString(s, "{{No audit entries found.}}::yellow"+CRLF)
+		return
+	}
+
+	for _, e := range entries {
+		var details []string
+		if e.Command != "" {
+			details = append(details, strings.TrimSpace(e.Command+" "+strings.Join(e.Args, " ")))
+		} else if len(e.Args) > 0 {
+			details = append(details, strings.Join(e.Args, " "))
+		}
+		if e.Action != "" {
+			details = append(details, "action="+e.Action)
+		}
+		if e.Target != "" {
+			details = append(details, "target="+e.Target)
+		}
+		if len(e.Entities) > 0 {
+			details = append(details, "entities="+strings.Join(e.Entities, ","))
+		}
+		if e.Value > 0 {
+			details = append(details, fmt.Sprintf("value=%d", e.Value))
+		}
+		if e.RoomID != "" {
+			details = append(details, "room="+e.RoomID)
+		}
+
+		WriteStringF(s, "{{%s}}::white {{%-16s}}::yellow {{%-12s}}::cyan %s"+CRLF,
+			e.Time.Format("2006-01-02 15:04:05"), e.Type, e.Actor, strings.Join(details, " "))
+	}
+}
+
+// DoInvis implements the admin "invis" command, toggling invisibility at the character's own level.
+// Usage: invis
+func DoInvis(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if char.InvisLevel > 0 {
+		setInvisLevel(s, char, room, 0)
+		return
+	}
+
+	setInvisLevel(s, char, room, char.GetPermissionLevel())
+}
+
+// DoWizinvis implements the admin "wizinvis" command, showing or setting the level below which characters
+// can't see them.
+// Usage: wizinvis [level]
+// e.g. "wizinvis 30", "wizinvis 0"
+func DoWizinvis(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		if char.InvisLevel > 0 {
+			WriteStringF(s, "{{You are invisible to characters below level %d.}}::cyan"+CRLF, char.InvisLevel)
+		} else {
+			WriteString(s, "{{You are visible to everyone.}}::cyan"+CRLF)
+		}
+		return
+	}
+
+	level, err := strconv.Atoi(args[0])
+	if err != nil || level < 0 {
+		WriteString(s, "{{Usage: wizinvis [level]}}::yellow"+CRLF)
+		return
+	}
+
+	if max := char.GetPermissionLevel(); level > max {
+		WriteStringF(s, "{{You can't go invisible above your own level of %d.}}::red"+CRLF, max)
+		return
+	}
+
+	setInvisLevel(s, char, room, level)
+}
+
+// setInvisLevel changes the character's invisibility, telling the room when the character fades in or out
+// for the characters that will no longer, or can now, see them.
+func setInvisLevel(s ssh.Session, char *Character, room *Room, level int) {
+	if room != nil && level > 0 && char.InvisLevel == 0 {
+		room.BroadcastAbout(char, cfmt.Sprintf("{{%s fades from view.}}::white"+CRLF, char.Name), []string{char.ID})
+	}
+
+	previous := char.InvisLevel
+	char.InvisLevel = level
+
+	if room != nil && level == 0 && previous > 0 {
+		room.BroadcastAbout(char, cfmt.Sprintf("{{%s fades into view.}}::white"+CRLF, char.Name), []string{char.ID})
+	}
+
+	char.Save()
+
+	if level > 0 {
+		WriteStringF(s, "{{You are now invisible to characters below level %d.}}::green"+CRLF, level)
+	} else {
+		WriteString(s, "{{You are now visible to everyone.}}::green"+CRLF)
+	}
+}
+
+// DoReload implements the admin "reload" command, reloading an area's data files on the next game tick.
+// Usage: reload area <area_id>
+// e.g. "reload area seattle"
+func DoReload(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) != 2 || !strings.EqualFold(args[0], "area") {
+		WriteString(s, "{{Usage: reload area <area_id>}}::yellow"+CRLF)
+		return
+	}
+
+	dir, err := EntityMgr.GetAreaDir(args[1])
+	if err != nil {
+		WriteStringF(s, "{{Unable to reload the area: %s}}::red"+CRLF, err.Error())
+		return
+	}
+
+	if err := <-EntityMgr.QueueAreaReload(dir); err != nil {
+		WriteStringF(s, "{{Unable to reload the area: %s}}::red"+CRLF, err.Error())
+		return
+	}
+
+	WriteStringF(s, "{{Reloaded area %s.}}::green"+CRLF, args[1])
+}
+
+func DoWorldLint(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	issues := EntityMgr.LintWorld(os.DirFS(viper.GetString("data.areas_path")))
+
+	shown := 0
+	for _, issue := range issues {
+		if len(args) > 0 && !strings.EqualFold(issue.AreaID, args[0]) {
+			continue
+		}
+		WriteString(s, issue.Format()+CRLF)
+		shown++
+	}
+
+	if shown == 0 {
+		WriteString(s, "{{No problems found.}}::green"+CRLF)
+		return
+	}
+
+	WriteStringF(s, "{{%d %s found.}}::yellow"+CRLF, shown, pluralizer.PluralizeNoun("issue", shown))
+}
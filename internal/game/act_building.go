@@ -0,0 +1,222 @@
+package game
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/i582/cfmt/cmd/cfmt"
+)
+
+/*
+Usage:
+  - redit
+  - redit <room_id>
+  - redit new <room_id>
+*/
+func DoRedit(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	target := room
+
+	switch {
+	case len(args) == 2 && strings.EqualFold(args[0], "new"):
+		id := strings.ToLower(args[1])
+		if EntityMgr.GetRoom(QualifyID(room.AreaID, id)) != nil {
+			WriteStringF(s, "{{A room with the ID %q already exists.}}::red"+CRLF, id)
+			return
+		}
+
+		target = &Room{
+			ID:           id,
+			AreaID:       room.AreaID,
+			Area:         room.Area,
+			Title:        "An Unfinished Room",
+			Description:  "Nothing has been built here yet.",
+			Exits:        make(map[string]*Exit),
+			Characters:   make(map[string]*Character),
+			MobInstances: make(map[string]*MobInstance),
+		}
+		EntityMgr.AddRoom(target)
+		AuditMgr.LogEntities(char, AuditTypeEntityCreated, cmd, []string{"room#" + id}, room)
+
+		WriteStringF(s, "{{Created room %q in %s.}}::green"+CRLF, id, room.AreaID)
+	case len(args) == 1:
+		target = EntityMgr.ResolveRoom(room.AreaID, args[0])
+		if target == nil {
+			WriteStringF(s, "{{There is no room %q.}}::red"+CRLF, args[0])
+			return
+		}
+	case len(args) > 0:
+		WriteString(s, "{{Usage: redit [room_id] | redit new <room_id>}}::yellow"+CRLF)
+		return
+	}
+
+	if err := EditRoom(s, target); err != nil {
+		slog.Error("Error editing room", slog.String("room_id", target.ID), slog.Any("error", err))
+		return
+	}
+
+	WriteStringF(s, "{{Finished editing %s. Use 'save area %s' to keep your changes.}}::green"+CRLF, target.ID, target.AreaID)
+}
+
+/*
+Usage:
+  - oedit <item_id>
+  - oedit new <item_id>
+*/
+func DoOedit(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	var bp *ItemBlueprint
+
+	switch {
+	case len(args) == 2 && strings.EqualFold(args[0], "new"):
+		id := strings.ToLower(args[1])
+		if EntityMgr.GetItemBlueprintByID(QualifyID(room.AreaID, id)) != nil {
+			WriteStringF(s, "{{An item with the ID %q already exists.}}::red"+CRLF, id)
+			return
+		}
+
+		bp = &ItemBlueprint{
+			ID:          id,
+			Name:        "an unfinished item",
+			Description: "Nothing has been made here yet.",
+			Type:        ItemTypeJunk,
+			EquipSlots:  []string{EquipSlotNone},
+			AreaID:      room.AreaID,
+		}
+		EntityMgr.AddItemBlueprint(bp)
+		AuditMgr.LogEntities(char, AuditTypeEntityCreated, cmd, []string{"item#" + id}, room)
+
+		WriteStringF(s, "{{Created item %q in %s.}}::green"+CRLF, id, room.AreaID)
+	case len(args) == 1:
+		bp = EntityMgr.ResolveItemBlueprint(room.AreaID, args[0])
+		if bp == nil {
+			WriteStringF(s, "{{There is no item %q.}}::red"+CRLF, args[0])
+			return
+		}
+	default:
+		WriteString(s, "{{Usage: oedit <item_id> | oedit new <item_id>}}::yellow"+CRLF)
+		return
+	}
+
+	if err := EditItemBlueprint(s, bp); err != nil {
+		slog.Error("Error editing item", slog.String("item_id", bp.ID), slog.Any("error", err))
+		return
+	}
+
+	WriteStringF(s, "{{Finished editing %s. Use 'save area %s' to keep your changes.}}::green"+CRLF, bp.ID, bp.AreaID)
+}
+
+/*
+Usage:
+  - medit <mob_id>
+  - medit new <mob_id>
+*/
+func DoMedit(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	var bp *MobBlueprint
+
+	switch {
+	case len(args) == 2 && strings.EqualFold(args[0], "new"):
+		id := strings.ToLower(args[1])
+		if EntityMgr.GetMobBlueprintByID(QualifyID(room.AreaID, id)) != nil {
+			WriteStringF(s, "{{A mob with the ID %q already exists.}}::red"+CRLF, id)
+			return
+		}
+
+		bp = &MobBlueprint{AreaID: room.AreaID}
+		bp.ID = id
+		bp.Name = "an unfinished mob"
+		bp.Description = "Nothing has been made here yet."
+		bp.MetatypeID = char.MetatypeID
+		bp.Metatype = EntityMgr.GetMetatype(char.MetatypeID)
+		bp.GeneralDisposition = DispositionNeutral
+		EntityMgr.AddMobBlueprint(bp)
+		AuditMgr.LogEntities(char, AuditTypeEntityCreated, cmd, []string{"mob#" + id}, room)
+
+		WriteStringF(s, "{{Created mob %q in %s.}}::green"+CRLF, id, room.AreaID)
+	case len(args) == 1:
+		bp = EntityMgr.ResolveMobBlueprint(room.AreaID, args[0])
+		if bp == nil {
+			WriteStringF(s, "{{There is no mob %q.}}::red"+CRLF, args[0])
+			return
+		}
+	default:
+		WriteString(s, "{{Usage: medit <mob_id> | medit new <mob_id>}}::yellow"+CRLF)
+		return
+	}
+
+	if err := EditMobBlueprint(s, bp); err != nil {
+		slog.Error("Error editing mob", slog.String("mob_id", bp.ID), slog.Any("error", err))
+		return
+	}
+
+	WriteStringF(s, "{{Finished editing %s. Use 'save area %s' to keep your changes.}}::green"+CRLF, bp.ID, bp.AreaID)
+}
+
+/*
+Usage:
+  - aedit
+  - aedit <area_id>
+  - aedit new <area_id>
+*/
+func DoAedit(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	area := room.Area
+
+	switch {
+	case len(args) == 2 && strings.EqualFold(args[0], "new"):
+		id := strings.ToLower(args[1])
+		if EntityMgr.GetArea(id) != nil {
+			WriteStringF(s, "{{An area with the ID %q already exists.}}::red"+CRLF, id)
+			return
+		}
+
+		area = &Area{ID: id, Title: Capitalize(id), Dir: id}
+		EntityMgr.AddArea(area)
+		AuditMgr.LogEntities(char, AuditTypeEntityCreated, cmd, []string{"area#" + id}, room)
+
+		WriteStringF(s, "{{Created area %q.}}::green"+CRLF, id)
+	case len(args) == 1:
+		area = EntityMgr.GetArea(args[0])
+		if area == nil {
+			WriteStringF(s, "{{There is no area %q.}}::red"+CRLF, args[0])
+			return
+		}
+	case len(args) > 0:
+		WriteString(s, "{{Usage: aedit [area_id] | aedit new <area_id>}}::yellow"+CRLF)
+		return
+	}
+
+	if area == nil {
+		WriteString(s, "{{This room isn't part of an area.}}::red"+CRLF)
+		return
+	}
+
+	if err := EditArea(s, area); err != nil {
+		slog.Error("Error editing area", slog.String("area_id", area.ID), slog.Any("error", err))
+		return
+	}
+
+	WriteStringF(s, "{{Finished editing %s. Use 'save area %s' to keep your changes.}}::green"+CRLF, area.ID, area.ID)
+}
+
+/*
+Usage:
+  - save area [area_id]
+*/
+func DoSave(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 || len(args) > 2 || !strings.EqualFold(args[0], "area") {
+		WriteString(s, "{{Usage: save area [area_id]}}::yellow"+CRLF)
+		return
+	}
+
+	areaID := room.AreaID
+	if len(args) == 2 {
+		areaID = args[1]
+	}
+
+	if err := EntityMgr.SaveArea(areaID); err != nil {
+		slog.Error("Error saving area", slog.String("area_id", areaID), slog.Any("error", err))
+		WriteStringF(s, "{{Unable to save the area: %s}}::red"+CRLF, err.Error())
+		return
+	}
+
+	WriteString(s, cfmt.Sprintf("{{Saved area %s.}}::green"+CRLF, areaID))
+}
@@ -1,18 +1,23 @@
 package game
 
 import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Jasrags/NewMUD/pluralizer"
 	"github.com/gliderlabs/ssh"
 	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/spf13/viper"
 )
 
 /*
 Usage:
   - say <message>
 */
-// TODO: overall for communication commands we need to log messages to a database with time, to/from, and message.
-// TODO: need to implement a block/unblock function for preventing messages from certain users
 func DoSay(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
 	if len(args) == 0 {
 		WriteString(s, "{{What do you want to say?}}::red"+CRLF)
@@ -20,63 +25,951 @@ func DoSay(s ssh.Session, cmd string, args []string, user *Account, char *Charac
 	}
 
 	message := strings.Join(args, " ")
+	language := char.GetSpeakingLanguage()
 
-	// Broadcast message to the room
-	room.Broadcast(cfmt.Sprintf("{{%s says: \"%s\"}}::green"+CRLF, char.Name, message), []string{char.ID})
+	// Broadcast message to the room, garbled for anyone who doesn't speak the language
+	recipients := room.BroadcastFromFunc(char, func(listener *Character) string {
+		return cfmt.Sprintf("{{%s says%s: \"%s\"}}::green"+CRLF, char.Name, languageTag(listener, language), GarbleText(message, listener.GetLanguageRating(language)))
+	}, []string{char.ID})
+	MessageLogMgr.Record(&MessageLogEntry{
+		Type:     MessageTypeSay,
+		Sender:   char.Name,
+		SenderID: char.ID,
+		RoomID:   room.ID,
+		Message:  message,
+	}, recipients)
 
 	// Message the player
-	WriteStringF(s, "{{You say: \"%s\"}}::green"+CRLF, message)
+	WriteStringF(s, "{{You say%s: \"%s\"}}::green"+CRLF, languageTag(char, language), message)
 }
 
+/*
+Usage:
+  - emote <action>
+*/
+func DoEmote(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		WriteString(s, "{{What do you want to do?}}::red"+CRLF)
+		return
+	}
+
+	msg := cfmt.Sprintf("{{%s %s}}::green"+CRLF, char.Name, strings.Join(args, " "))
+	room.BroadcastFrom(char, msg, []string{char.ID})
+	char.Send(msg)
+}
+
+/*
+Usage:
+  - <social>
+  - <social> <target>
+  - <social> self
+*/
+func DoSocial(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	social := SocialMgr.GetSocial(cmd)
+	if social == nil {
+		WriteStringF(s, "{{Unknown command '%s'. Type 'help' for a list of commands.}}::red"+CRLF, cmd)
+		return
+	}
+
+	actor := &char.GameEntityInformation
+	if len(args) == 0 {
+		char.Send(cfmt.Sprintf("{{%s}}::green"+CRLF, ActMessage(social.NoTarget.Actor, actor, nil)))
+		if social.NoTarget.Room != "" {
+			room.BroadcastFrom(char, cfmt.Sprintf("{{%s}}::green"+CRLF, ActMessage(social.NoTarget.Room, actor, nil)), []string{char.ID})
+		}
+		return
+	}
+
+	name := strings.Join(args, " ")
+	if strings.EqualFold(name, "self") || strings.EqualFold(name, "me") || strings.EqualFold(name, char.Name) {
+		if !social.HasSelf() {
+			WriteStringF(s, "{{You can't %s yourself.}}::yellow"+CRLF, social.ID)
+			return
+		}
+
+		char.Send(cfmt.Sprintf("{{%s}}::green"+CRLF, ActMessage(social.Self.Actor, actor, actor)))
+		if social.Self.Room != "" {
+			room.BroadcastFrom(char, cfmt.Sprintf("{{%s}}::green"+CRLF, ActMessage(social.Self.Room, actor, actor)), []string{char.ID})
+		}
+		return
+	}
+
+	if !social.HasTarget() {
+		WriteStringF(s, "{{You can't %s at anyone.}}::yellow"+CRLF, social.ID)
+		return
+	}
+
+	// Target a character first, then fall back to the mobs in the room
+	var target *GameEntityInformation
+	excludeIDs := []string{char.ID}
+	if c := room.FindCharacterByName(name); c != nil && char.CanSee(c) {
+		target = &c.GameEntityInformation
+		excludeIDs = append(excludeIDs, c.ID)
+		if social.Target.Target != "" {
+			c.SendFrom(char, cfmt.Sprintf("{{%s}}::green"+CRLF, ActMessage(social.Target.Target, actor, target)))
+		}
+	} else if mobs := room.FindMobsByPartialName(name); len(mobs) > 0 {
+		target = &mobs[0].Blueprint.GameEntityInformation
+	}
+
+	if target == nil {
+		WriteStringF(s, "{{There is no one named '%s' here.}}::yellow"+CRLF, name)
+		return
+	}
+
+	char.Send(cfmt.Sprintf("{{%s}}::green"+CRLF, ActMessage(social.Target.Actor, actor, target)))
+	if social.Target.Room != "" {
+		room.BroadcastFrom(char, cfmt.Sprintf("{{%s}}::green"+CRLF, ActMessage(social.Target.Room, actor, target)), excludeIDs)
+	}
+}
+
+func SuggestSocial(line string, args []string, char *Character, room *Room) []string {
+	suggestions := []string{}
+
+	if len(args) == 0 {
+		for _, c := range room.Characters {
+			if c.ID != char.ID && char.CanSee(c) {
+				suggestions = append(suggestions, c.Name)
+			}
+		}
+		for _, m := range room.MobInstances {
+			suggestions = append(suggestions, m.Blueprint.Name)
+		}
+	}
+
+	return suggestions
+}
+
+func DoSocials(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	WriteString(s, "{{Socials:}}::white|bold"+CRLF)
+	for _, social := range SocialMgr.GetSocials() {
+		WriteStringF(s, "  {{%-12s}}::cyan %s"+CRLF, social.ID, social.Description)
+	}
+}
+
+/*
+Usage:
+  - tell <character> <message>
+*/
 func DoTell(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
 	if len(args) < 2 {
-		WriteString(s, "{{Usage: tell <username> <message>.}}::red"+CRLF)
+		WriteString(s, "{{Usage: tell <character> <message>}}::yellow"+CRLF)
 		return
 	}
 
-	recipientName := args[0]
-	message := strings.Join(args[1:], " ")
+	sendTell(s, char, room, args[0], strings.Join(args[1:], " "))
+}
 
-	var recipient *Character
-	for _, r := range room.Characters {
-		if strings.EqualFold(r.Name, recipientName) {
-			recipient = r
-			break
-		}
+/*
+Usage:
+  - reply <message>
+*/
+func DoReply(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		WriteString(s, "{{Usage: reply <message>}}::yellow"+CRLF)
+		return
+	}
+
+	char.RLock()
+	replyTo := char.ReplyTo
+	char.RUnlock()
+
+	if replyTo == "" {
+		WriteString(s, "{{No one has sent you a tell to reply to.}}::yellow"+CRLF)
+		return
 	}
 
+	sendTell(s, char, room, replyTo, strings.Join(args, " "))
+}
+
+// sendTell delivers a tell to any online character the sender can see, queueing it for their next login
+// otherwise.
+func sendTell(s ssh.Session, char *Character, room *Room, recipientName, message string) {
+	recipient := CharacterMgr.GetCharacterByName(recipientName)
 	if recipient == nil {
-		WriteStringF(s, "{{There is no one named '%s' here.}}::yellow"+CRLF, recipientName)
+		WriteStringF(s, "{{There is no character named '%s'.}}::yellow"+CRLF, recipientName)
+		return
+	}
+
+	if recipient == char {
+		WriteString(s, "{{You talk to yourself.}}::yellow"+CRLF)
+		return
+	}
+
+	// Characters hidden from the sender are treated as offline so tells don't give them away
+	language := char.GetSpeakingLanguage()
+	if !CharacterMgr.IsOnline(recipient.Name) || !char.CanSee(recipient) {
+		// Tells from ignored characters are dropped without letting the sender know
+		if !recipient.IsIgnoring(char) {
+			if !recipient.QueueTell(char.Name, message, language) {
+				WriteStringF(s, "{{%s has too many tells waiting. Try again after they next log in.}}::yellow"+CRLF, recipient.Name)
+				return
+			}
+			recipient.Save()
+		}
+		logMessage(MessageTypeTell, char, recipient, room, message, false)
+
+		WriteStringF(s, "{{%s is offline. Your tell will be delivered when they next log in.}}::yellow"+CRLF, recipient.Name)
+		return
+	}
+
+	delivered := recipient.SendFrom(char, cfmt.Sprintf("{{%s tells you%s: \"%s\"}}::cyan"+CRLF,
+		char.Name, languageTag(recipient, language), GarbleText(message, recipient.GetLanguageRating(language))))
+	if delivered {
+		recipient.ReceiveTell(char.Name)
+	}
+	logMessage(MessageTypeTell, char, recipient, room, message, delivered)
+
+	WriteStringF(s, "{{You tell %s%s: \"%s\"}}::green"+CRLF, recipient.Name, languageTag(char, language), message)
+
+	if afk, afkMessage := recipient.IsAFK(); afk && delivered {
+		if afkMessage == "" {
+			afkMessage = "I am away from the keyboard."
+		}
+		WriteStringF(s, "{{%s is AFK and auto-replies: \"%s\"}}::cyan"+CRLF, recipient.Name, afkMessage)
+	}
+}
+
+// languageTag describes the language being spoken to a listener. Nothing is added for their native
+// languages, and languages they have no rating in can't be identified.
+func languageTag(listener *Character, languageID string) string {
+	if slices.Contains(listener.GetNativeLanguages(), languageID) {
+		return ""
+	}
+
+	if listener.GetLanguageRating(languageID) == 0 {
+		return " in a language you don't understand"
+	}
+
+	return " in " + languageName(languageID)
+}
+
+/*
+Usage:
+  - speak
+  - speak <language>
+*/
+func DoSpeak(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		WriteStringF(s, "{{You are speaking %s.}}::green"+CRLF, languageName(char.GetSpeakingLanguage()))
+
+		var known []string
+		for _, bp := range EntityMgr.GetLanguages() {
+			rating := char.GetLanguageRating(bp.ID)
+			switch {
+			case rating >= LanguageNativeRating:
+				known = append(known, bp.Name+" (native)")
+			case rating > 0:
+				known = append(known, fmt.Sprintf("%s (%d)", bp.Name, rating))
+			}
+		}
+		WriteStringF(s, "{{Languages you know:}}::white|bold %s"+CRLF, strings.Join(known, ", "))
+		return
+	}
+
+	bp := EntityMgr.FindLanguage(strings.Join(args, " "))
+	if bp == nil {
+		WriteStringF(s, "{{There is no language called '%s'.}}::red"+CRLF, strings.Join(args, " "))
+		return
+	}
+
+	if char.GetLanguageRating(bp.ID) == 0 {
+		WriteStringF(s, "{{You don't know how to speak %s.}}::red"+CRLF, bp.Name)
 		return
 	}
 
-	// Message the recipient
-	WriteStringF(recipient.Conn, "{{%s tells you: \"%s\"}}::cyan"+CRLF, char.Name, message)
+	char.Language = bp.ID
+	char.Save()
 
-	// Message the sender
-	WriteStringF(s, "{{You tell %s: \"%s\"}}::green"+CRLF, recipient.Name, message)
+	WriteStringF(s, "{{You are now speaking %s.}}::green"+CRLF, bp.Name)
+}
+
+// languageName returns the display name of a language.
+func languageName(languageID string) string {
+	if bp := EntityMgr.GetSkillBlueprint(languageID); bp != nil {
+		return bp.Name
+	}
+
+	return Capitalize(languageID)
+}
+
+// logMessage records a private message in the message log, adding it to the recipient's replay buffer
+// if it was delivered.
+func logMessage(msgType string, sender, recipient *Character, room *Room, message string, delivered bool) {
+	var recipients []*Character
+	if delivered {
+		recipients = append(recipients, recipient)
+	}
 
-	// Message the room (excluding sender and recipient)
-	room.Broadcast(cfmt.Sprintf("{{%s tells %s something privately.}}::green"+CRLF, char.Name, recipient.Name), []string{char.ID, recipient.ID})
+	MessageLogMgr.Record(&MessageLogEntry{
+		Type:      msgType,
+		Sender:    sender.Name,
+		SenderID:  sender.ID,
+		Recipient: recipient.Name,
+		RoomID:    auditRoomID(room),
+		Message:   message,
+	}, recipients)
 }
 
 func SuggestTell(line string, args []string, char *Character, room *Room) []string {
 	suggestions := []string{}
 
 	switch len(args) {
-	case 0: // Suggest names of characters in the room
-		for _, r := range room.Characters {
-			if !strings.EqualFold(r.Name, char.Name) { // Exclude self
-				suggestions = append(suggestions, r.Name)
+	case 0: // Suggest names of online characters
+		for _, c := range CharacterMgr.GetOnlineCharacters() {
+			if !strings.EqualFold(c.Name, char.Name) && char.CanSee(c) { // Exclude self
+				suggestions = append(suggestions, c.Name)
 			}
 		}
 	case 1: // Suggest partial names
-		for _, r := range room.Characters {
-			if !strings.EqualFold(r.Name, char.Name) && strings.HasPrefix(strings.ToLower(r.Name), strings.ToLower(args[0])) {
-				suggestions = append(suggestions, r.Name)
+		for _, c := range CharacterMgr.GetOnlineCharacters() {
+			if !strings.EqualFold(c.Name, char.Name) && char.CanSee(c) && strings.HasPrefix(strings.ToLower(c.Name), strings.ToLower(args[0])) {
+				suggestions = append(suggestions, c.Name)
 			}
 		}
 	}
 
 	return suggestions
 }
+
+/*
+Usage:
+  - afk
+  - afk <message>
+*/
+func DoAFK(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if afk, _ := char.IsAFK(); afk && len(args) == 0 {
+		returnFromAFK(char)
+		return
+	}
+
+	message := strings.Join(args, " ")
+	char.SetAFK(message)
+
+	if message == "" {
+		WriteString(s, "{{You are now AFK.}}::green"+CRLF)
+	} else {
+		WriteStringF(s, "{{You are now AFK: %s}}::green"+CRLF, message)
+	}
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s is now away from the keyboard.}}::green"+CRLF, char.Name), []string{char.ID})
+}
+
+// returnFromAFK clears the character's AFK status and lets them know about tells they missed.
+func returnFromAFK(char *Character) {
+	tells := char.ClearAFK()
+
+	char.Send(cfmt.Sprint("{{You are no longer AFK.}}::green" + CRLF))
+	if tells > 0 {
+		char.Send(cfmt.Sprintf("{{You received %d %s while you were away. Type 'lasttells' to read them.}}::cyan"+CRLF,
+			tells, pluralizer.PluralizeNoun("tell", tells)))
+	}
+	if char.Room != nil {
+		char.Room.BroadcastAbout(char, cfmt.Sprintf("{{%s has returned to the keyboard.}}::green"+CRLF, char.Name), []string{char.ID})
+	}
+}
+
+/*
+Usage:
+  - <channel> <message>
+  - <channel> last [count]
+  - <channel> join
+  - <channel> leave
+  - <channel> who
+*/
+func DoChannel(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	ch := ChannelMgr.GetChannel(cmd)
+	if ch == nil || !ch.CanAccess(char) {
+		WriteStringF(s, "{{Unknown command '%s'. Type 'help' for a list of commands.}}::red"+CRLF, cmd)
+		return
+	}
+
+	if len(args) == 0 {
+		WriteStringF(s, "{{Usage: %s <message> | last [count] | join | leave | who}}::yellow"+CRLF, ch.ID)
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "join":
+		if len(args) > 1 {
+			break
+		}
+		char.SetChannelMembership(ch.ID, true)
+		char.Save()
+		WriteStringF(s, "{{You join the %s channel.}}::green"+CRLF, ch.Name)
+		return
+	case "leave":
+		if len(args) > 1 {
+			break
+		}
+		char.SetChannelMembership(ch.ID, false)
+		char.Save()
+		WriteStringF(s, "{{You leave the %s channel.}}::green"+CRLF, ch.Name)
+		return
+	case "who":
+		if len(args) > 1 {
+			break
+		}
+		var names []string
+		for _, c := range CharacterMgr.GetOnlineCharacters() {
+			if ch.CanReceive(char, c) {
+				names = append(names, c.Name)
+			}
+		}
+		sort.Strings(names)
+		WriteStringF(s, "{{Listening to %s:}}::white|bold {{%s}}::cyan"+CRLF, ch.Name, strings.Join(names, ", "))
+		return
+	case "last", "history":
+		count := 10
+		if len(args) > 2 {
+			break
+		}
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				break
+			}
+			count = n
+		}
+
+		messages := ch.GetHistory(char, count)
+		if len(messages) == 0 {
+			WriteStringF(s, "{{There are no recent messages on %s.}}::yellow"+CRLF, ch.Name)
+			return
+		}
+		for _, msg := range messages {
+			WriteStringF(s, "{{%s}}::white %s"+CRLF, msg.Time.Format("15:04"), ch.Format(msg))
+		}
+		return
+	}
+
+	if ch.Scope == ChannelScopeTeam && TeamMgr.GetTeam(char) == nil {
+		WriteString(s, "{{You are not in a team.}}::yellow"+CRLF)
+		return
+	}
+
+	if !ch.IsMember(char) {
+		WriteStringF(s, "{{You are not on the %s channel. Type '%s join' to join it.}}::yellow"+CRLF, ch.Name, ch.ID)
+		return
+	}
+
+	ChannelMgr.Send(ch, char, strings.Join(args, " "))
+}
+
+func DoChannels(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	WriteString(s, "{{Channels:}}::white|bold"+CRLF)
+	for _, ch := range ChannelMgr.GetChannels() {
+		if !ch.CanAccess(char) {
+			continue
+		}
+
+		status := "{{off}}::red"
+		if ch.IsMember(char) {
+			status = "{{on}}::green"
+		}
+
+		color := ch.Color
+		if color == "" {
+			color = ChannelDefaultColor
+		}
+
+		WriteStringF(s, "{{%-10s}}::%s %-3s {{%-6s}}::white {{%s}}::cyan"+CRLF, ch.ID, color, status, ch.Scope, ch.Description)
+	}
+}
+
+// comlinkRecipient finds an online recipient for a comlink message, making sure both sides have a comlink.
+func comlinkRecipient(s ssh.Session, char *Character, name string) *Character {
+	if char.GetComlink() == nil {
+		WriteString(s, "{{You need a comlink to do that.}}::red"+CRLF)
+		return nil
+	}
+
+	recipient, ok := CharacterMgr.GetOnlineCharacters()[strings.ToLower(name)]
+	if !ok || recipient.Conn == nil || !char.CanSee(recipient) {
+		WriteStringF(s, "{{Your comlink can't find anyone named '%s' on the grid.}}::yellow"+CRLF, name)
+		return nil
+	}
+
+	if recipient == char {
+		WriteString(s, "{{You can't reach yourself.}}::yellow"+CRLF)
+		return nil
+	}
+
+	if recipient.GetComlink() == nil {
+		WriteStringF(s, "{{%s doesn't have a comlink on them.}}::yellow"+CRLF, recipient.Name)
+		return nil
+	}
+
+	return recipient
+}
+
+/*
+Usage:
+  - replay [count]
+  - lasttells [count]
+*/
+func DoReplay(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	count := 20
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			WriteStringF(s, "{{Usage: %s [count]}}::yellow"+CRLF, cmd)
+			return
+		}
+		count = n
+	}
+
+	msgType := ""
+	if cmd == "lasttells" {
+		msgType = MessageTypeTell
+	}
+
+	entries := MessageLogMgr.Replay(char, count, msgType)
+	if len(entries) == 0 {
+		WriteString(s, "{{You have no recent messages.}}::yellow"+CRLF)
+		return
+	}
+
+	for _, e := range entries {
+		WriteStringF(s, "{{%s}}::white %s"+CRLF, e.Time.Format("15:04"), e.Format())
+	}
+}
+
+/*
+Usage:
+  - mail [list]
+  - mail read <number>
+  - mail send <character> [subject]
+  - mail delete <number>
+*/
+func DoMail(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list":
+		user.RLock()
+		mail := slices.Clone(user.Mail)
+		user.RUnlock()
+
+		if len(mail) == 0 {
+			WriteString(s, "{{Your mailbox is empty.}}::yellow"+CRLF)
+			return
+		}
+
+		WriteString(s, "{{Mailbox:}}::white|bold"+CRLF)
+		for i, msg := range mail {
+			status := " "
+			if !msg.Read {
+				status = cfmt.Sprint("{{*}}::cyan|bold")
+			}
+			WriteStringF(s, "%s{{%3d.}}::white {{%s}}::white {{%-12s}}::cyan {{%-12s}}::green %s"+CRLF,
+				status, i+1, msg.SentAt.Format("2006-01-02 15:04"), msg.From, msg.To, msg.Subject)
+		}
+	case "read":
+		if len(args) != 2 {
+			WriteString(s, "{{Usage: mail read <number>}}::yellow"+CRLF)
+			return
+		}
+
+		n, err := strconv.Atoi(args[1])
+		msg := user.GetMail(n - 1)
+		if err != nil || msg == nil {
+			WriteStringF(s, "{{There is no message number '%s'.}}::red"+CRLF, args[1])
+			return
+		}
+
+		WriteStringF(s, "{{From:}}::white|bold %s"+CRLF, msg.From)
+		WriteStringF(s, "{{To:}}::white|bold %s"+CRLF, msg.To)
+		WriteStringF(s, "{{Date:}}::white|bold %s"+CRLF, msg.SentAt.Format("2006-01-02 15:04"))
+		WriteStringF(s, "{{Subject:}}::white|bold %s"+CRLF+CRLF, msg.Subject)
+		WriteString(s, strings.ReplaceAll(msg.Body, "\n", CRLF)+CRLF)
+
+		if !msg.Read {
+			user.Lock()
+			msg.Read = true
+			user.Unlock()
+			user.Save()
+		}
+	case "send":
+		if len(args) < 2 {
+			WriteString(s, "{{Usage: mail send <character> [subject]}}::yellow"+CRLF)
+			return
+		}
+
+		recipient := CharacterMgr.GetCharacterByName(args[1])
+		if recipient == nil {
+			WriteStringF(s, "{{There is no character named '%s'.}}::red"+CRLF, args[1])
+			return
+		}
+
+		subject := strings.Join(args[2:], " ")
+		if subject == "" {
+			subject = "(no subject)"
+		}
+
+		body, ok, err := EditorPrompt(s, cfmt.Sprintf("Mail to %s: %s", recipient.Name, subject))
+		if err != nil || !ok {
+			return
+		}
+		if strings.TrimSpace(body) == "" {
+			WriteString(s, "{{Your message is empty, it was not sent.}}::yellow"+CRLF)
+			return
+		}
+
+		if err := SendMail(char, recipient.Name, subject, body); err != nil {
+			WriteStringF(s, "{{Your mail could not be delivered: %s.}}::red"+CRLF, err.Error())
+			return
+		}
+
+		WriteStringF(s, "{{Your mail to %s has been sent.}}::green"+CRLF, recipient.Name)
+	case "delete":
+		if len(args) != 2 {
+			WriteString(s, "{{Usage: mail delete <number>}}::yellow"+CRLF)
+			return
+		}
+
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			WriteStringF(s, "{{There is no message number '%s'.}}::red"+CRLF, args[1])
+			return
+		}
+		if err := user.DeleteMail(n - 1); err != nil {
+			WriteStringF(s, "{{%s.}}::red"+CRLF, Capitalize(err.Error()))
+			return
+		}
+		user.Save()
+
+		WriteStringF(s, "{{Message %d deleted.}}::green"+CRLF, n)
+	default:
+		WriteString(s, "{{Usage: mail [list] | read <number> | send <character> [subject] | delete <number>}}::yellow"+CRLF)
+	}
+}
+
+/*
+Usage:
+  - board [list]
+  - board read <number>
+  - board post <subject>
+  - board remove <number>
+*/
+func DoBoard(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	board, bp := BoardMgr.GetRoomBoard(room)
+	if board == nil {
+		WriteString(s, "{{There is no board here.}}::yellow"+CRLF)
+		return
+	}
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list":
+		board.RLock()
+		messages := slices.Clone(board.Messages)
+		board.RUnlock()
+
+		WriteStringF(s, "{{%s}}::white|bold"+CRLF, bp.Name)
+		if len(messages) == 0 {
+			WriteString(s, "{{The board is empty.}}::yellow"+CRLF)
+			return
+		}
+		for i, msg := range messages {
+			WriteStringF(s, "{{%3d.}}::white {{%s}}::white {{%-12s}}::cyan %s"+CRLF,
+				i+1, msg.PostedAt.Format("2006-01-02"), msg.Author, msg.Subject)
+		}
+	case "read":
+		if len(args) != 2 {
+			WriteString(s, "{{Usage: board read <number>}}::yellow"+CRLF)
+			return
+		}
+
+		n, err := strconv.Atoi(args[1])
+		msg := board.GetMessage(n - 1)
+		if err != nil || msg == nil {
+			WriteStringF(s, "{{There is no message number '%s'.}}::red"+CRLF, args[1])
+			return
+		}
+
+		WriteStringF(s, "{{%s}}::white|bold {{by %s on %s}}::cyan"+CRLF+CRLF, msg.Subject, msg.Author, msg.PostedAt.Format("2006-01-02 15:04"))
+		WriteString(s, strings.ReplaceAll(msg.Body, "\n", CRLF)+CRLF)
+	case "post":
+		if len(args) < 2 {
+			WriteString(s, "{{Usage: board post <subject>}}::yellow"+CRLF)
+			return
+		}
+
+		subject := strings.Join(args[1:], " ")
+		body, ok, err := EditorPrompt(s, cfmt.Sprintf("Posting to %s: %s", bp.Name, subject))
+		if err != nil || !ok {
+			return
+		}
+		if strings.TrimSpace(body) == "" {
+			WriteString(s, "{{Your post is empty, it was not added.}}::yellow"+CRLF)
+			return
+		}
+
+		board.Post(&BoardMessage{
+			Author:   char.Name,
+			Subject:  subject,
+			Body:     body,
+			PostedAt: time.Now(),
+		}, viper.GetInt("server.max_board_messages"))
+		if err := BoardMgr.SaveBoard(board); err != nil {
+			WriteString(s, "{{Failed to save the board.}}::red"+CRLF)
+			return
+		}
+
+		WriteString(s, "{{You pin your message to the board.}}::green"+CRLF)
+		room.BroadcastAbout(char, cfmt.Sprintf("{{%s pins a message to the %s.}}::green"+CRLF, char.Name, strings.ToLower(bp.Name)), []string{char.ID})
+	case "remove":
+		if len(args) != 2 {
+			WriteString(s, "{{Usage: board remove <number>}}::yellow"+CRLF)
+			return
+		}
+
+		n, err := strconv.Atoi(args[1])
+		msg := board.GetMessage(n - 1)
+		if err != nil || msg == nil {
+			WriteStringF(s, "{{There is no message number '%s'.}}::red"+CRLF, args[1])
+			return
+		}
+
+		if !strings.EqualFold(msg.Author, char.Name) && !char.HasPermission(PermissionAdminBoards) {
+			WriteString(s, "{{You can only remove your own messages.}}::red"+CRLF)
+			return
+		}
+
+		if err := board.RemoveMessage(n - 1); err != nil {
+			WriteStringF(s, "{{%s.}}::red"+CRLF, Capitalize(err.Error()))
+			return
+		}
+		BoardMgr.SaveBoard(board)
+
+		WriteStringF(s, "{{Message %d removed.}}::green"+CRLF, n)
+	default:
+		WriteString(s, "{{Usage: board [list] | read <number> | post <subject> | remove <number>}}::yellow"+CRLF)
+	}
+}
+
+/*
+Usage:
+  - ignore
+  - ignore <character>
+*/
+func DoIgnore(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		if len(user.Ignored) == 0 {
+			WriteString(s, "{{You are not ignoring anyone.}}::green"+CRLF)
+			return
+		}
+
+		WriteString(s, "{{You are ignoring:}}::white|bold"+CRLF)
+		for _, name := range user.Ignored {
+			WriteStringF(s, "  %s"+CRLF, Capitalize(name))
+		}
+		return
+	}
+
+	target := CharacterMgr.GetCharacterByName(args[0])
+	if target == nil {
+		WriteStringF(s, "{{There is no character named '%s'.}}::red"+CRLF, args[0])
+		return
+	}
+
+	if target.AccountID != "" && target.AccountID == char.AccountID {
+		WriteString(s, "{{You can't ignore your own characters.}}::red"+CRLF)
+		return
+	}
+
+	if !user.Ignore(target.Name) {
+		WriteStringF(s, "{{You are already ignoring %s.}}::yellow"+CRLF, target.Name)
+		return
+	}
+
+	if err := user.Save(); err != nil {
+		WriteString(s, "{{Failed to save your ignore list.}}::red"+CRLF)
+		return
+	}
+
+	WriteStringF(s, "{{You are now ignoring %s.}}::green"+CRLF, target.Name)
+	if target.HasPermission(PermissionBypassIgnore) {
+		WriteStringF(s, "{{Messages from staff such as %s will still reach you.}}::yellow"+CRLF, target.Name)
+	}
+}
+
+/*
+Usage:
+  - unignore <character>
+*/
+func DoUnignore(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		WriteString(s, "{{Usage: unignore <character>}}::yellow"+CRLF)
+		return
+	}
+
+	if !user.Unignore(args[0]) {
+		WriteStringF(s, "{{You are not ignoring '%s'.}}::yellow"+CRLF, args[0])
+		return
+	}
+
+	if err := user.Save(); err != nil {
+		WriteString(s, "{{Failed to save your ignore list.}}::red"+CRLF)
+		return
+	}
+
+	WriteStringF(s, "{{You are no longer ignoring %s.}}::green"+CRLF, Capitalize(strings.ToLower(args[0])))
+}
+
+/*
+Usage:
+  - text <character> <message>
+*/
+func DoText(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) < 2 {
+		WriteString(s, "{{Usage: text <character> <message>}}::yellow"+CRLF)
+		return
+	}
+
+	recipient := comlinkRecipient(s, char, args[0])
+	if recipient == nil {
+		return
+	}
+
+	message := strings.Join(args[1:], " ")
+
+	delivered := recipient.SendFrom(char, cfmt.Sprintf("{{Your comlink buzzes with a text from %s: \"%s\"}}::cyan"+CRLF, char.Name, message))
+	logMessage(MessageTypeText, char, recipient, room, message, delivered)
+	WriteStringF(s, "{{You text %s: \"%s\"}}::green"+CRLF, recipient.Name, message)
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s taps out a message on their comlink.}}::green"+CRLF, char.Name), []string{char.ID})
+}
+
+/*
+Usage:
+  - call <character> <message>
+*/
+func DoCall(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) < 2 {
+		WriteString(s, "{{Usage: call <character> <message>}}::yellow"+CRLF)
+		return
+	}
+
+	recipient := comlinkRecipient(s, char, args[0])
+	if recipient == nil {
+		return
+	}
+
+	message := strings.Join(args[1:], " ")
+
+	// Calls are spoken aloud so both rooms hear one side of the conversation
+	delivered := recipient.SendFrom(char, cfmt.Sprintf("{{%s's voice comes over your comlink: \"%s\"}}::cyan"+CRLF, char.Name, message))
+	logMessage(MessageTypeCall, char, recipient, room, message, delivered)
+	WriteStringF(s, "{{You say into your comlink to %s: \"%s\"}}::green"+CRLF, recipient.Name, message)
+	room.BroadcastFrom(char, cfmt.Sprintf("{{%s says into their comlink: \"%s\"}}::green"+CRLF, char.Name, message), []string{char.ID, recipient.ID})
+	if recipient.Room != nil && recipient.Room != room {
+		recipient.Room.Broadcast(cfmt.Sprintf("{{A voice crackles from %s's comlink.}}::green"+CRLF, recipient.Name), []string{recipient.ID})
+	}
+}
+
+/*
+Usage:
+  - team
+  - team invite <character>
+  - team accept <character>
+  - team leave
+  - team kick <character>
+*/
+func DoTeam(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		t := TeamMgr.GetTeam(char)
+		if t == nil {
+			WriteString(s, "{{You are not in a team.}}::yellow"+CRLF)
+			return
+		}
+
+		WriteString(s, "{{Your team:}}::white|bold"+CRLF)
+		for _, member := range t.GetMembers() {
+			status := "{{offline}}::red"
+			if member.Conn != nil {
+				status = "{{online}}::green"
+			}
+			if member.Room != nil {
+				status += cfmt.Sprintf(" {{%s}}::white", member.Room.Title)
+			}
+
+			role := "member"
+			if t.IsLeader(member) {
+				role = "leader"
+			}
+
+			WriteStringF(s, "{{%-15s}}::cyan {{%-6s}}::yellow %s"+CRLF, member.Name, role, status)
+		}
+		return
+	}
+
+	subcommand := strings.ToLower(args[0])
+	if subcommand == "leave" {
+		t, err := TeamMgr.Leave(char)
+		if err != nil {
+			WriteStringF(s, "{{%s.}}::red"+CRLF, Capitalize(err.Error()))
+			return
+		}
+
+		WriteString(s, "{{You leave the team.}}::green"+CRLF)
+		notifyTeam(t, char, cfmt.Sprintf("{{%s has left the team.}}::yellow"+CRLF, char.Name))
+		return
+	}
+
+	if len(args) != 2 {
+		WriteString(s, "{{Usage: team [invite|accept|kick <character>|leave]}}::yellow"+CRLF)
+		return
+	}
+
+	target, ok := CharacterMgr.GetOnlineCharacters()[strings.ToLower(args[1])]
+	if !ok {
+		WriteStringF(s, "{{No one named '%s' is online.}}::red"+CRLF, args[1])
+		return
+	}
+
+	switch subcommand {
+	case "invite":
+		if _, err := TeamMgr.Invite(char, target); err != nil {
+			WriteStringF(s, "{{%s.}}::red"+CRLF, Capitalize(err.Error()))
+			return
+		}
+
+		WriteStringF(s, "{{You invite %s to join your team.}}::green"+CRLF, target.Name)
+		target.Send(cfmt.Sprintf("{{%s invites you to join their team. Type 'team accept %s' to join.}}::cyan"+CRLF, char.Name, char.Name))
+	case "accept":
+		t, err := TeamMgr.Accept(char, target)
+		if err != nil {
+			WriteStringF(s, "{{%s.}}::red"+CRLF, Capitalize(err.Error()))
+			return
+		}
+
+		WriteStringF(s, "{{You join %s's team.}}::green"+CRLF, target.Name)
+		notifyTeam(t, char, cfmt.Sprintf("{{%s has joined the team.}}::green"+CRLF, char.Name))
+	case "kick":
+		t, err := TeamMgr.Kick(char, target)
+		if err != nil {
+			WriteStringF(s, "{{%s.}}::red"+CRLF, Capitalize(err.Error()))
+			return
+		}
+
+		WriteStringF(s, "{{You kick %s from the team.}}::green"+CRLF, target.Name)
+		target.Send(cfmt.Sprintf("{{%s has removed you from the team.}}::yellow"+CRLF, char.Name))
+		notifyTeam(t, char, cfmt.Sprintf("{{%s has been removed from the team.}}::yellow"+CRLF, target.Name))
+	default:
+		WriteString(s, "{{Usage: team [invite|accept|kick <character>|leave]}}::yellow"+CRLF)
+	}
+}
+
+// notifyTeam sends a message to every online member of the team except the given character.
+func notifyTeam(t *Team, except *Character, msg string) {
+	for _, member := range t.GetMembers() {
+		if member != except && member.Conn != nil {
+			member.Send(msg)
+		}
+	}
+}
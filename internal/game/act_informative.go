@@ -3,7 +3,10 @@ package game
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Jasrags/NewMUD/pluralizer"
 	"github.com/gliderlabs/ssh"
@@ -57,6 +60,11 @@ func DoLook(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		return
 	}
 
+	if !char.CanSeeIn(room) {
+		WriteString(s, "{{It is too dark to see anything.}}::red"+CRLF)
+		return
+	}
+
 	target := strings.Join(args, " ")
 
 	// Check if the target is an item in the room
@@ -72,13 +80,13 @@ func DoLook(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	}
 
 	// Check if the target is another character in the room
-	if targetChar := room.FindCharacterByName(target); targetChar != nil {
+	if targetChar := room.FindCharacterByName(target); targetChar != nil && char.CanSee(targetChar) {
 		WriteString(s, RenderCharacterDescription(targetChar))
 		return
 	}
 
 	// Check if the target is a direction
-	if room.HasExit(target) {
+	if _, ok := room.GetVisibleExit(char, target); ok {
 		WriteString(s, RenderExitDescription(target))
 		return
 	}
@@ -114,7 +122,9 @@ func SuggestLook(line string, args []string, char *Character, room *Room) []stri
 				}
 			}
 			for _, e := range room.Exits {
-				suggestions = append(suggestions, e.Direction)
+				if e.VisibleTo(char, room) {
+					suggestions = append(suggestions, e.Direction)
+				}
 			}
 		} else {
 			// Suggest items, mobs, characters, and directions directly
@@ -135,7 +145,7 @@ func SuggestLook(line string, args []string, char *Character, room *Room) []stri
 				}
 			}
 			for _, e := range room.Exits {
-				if strings.HasPrefix(strings.ToLower(e.Direction), strings.ToLower(args[0])) {
+				if e.VisibleTo(char, room) && strings.HasPrefix(strings.ToLower(e.Direction), strings.ToLower(args[0])) {
 					suggestions = append(suggestions, e.Direction)
 				}
 			}
@@ -267,32 +277,84 @@ func DoEquipment(s ssh.Session, cmd string, args []string, user *Account, char *
 /*
 Usage:
   - who
+  - who <name|role|afk>
+  - who metatype:<metatype> area:<area> role:<role>
+  - who sort:<level|name|idle|area>
 */
-// TODO: Sort all admins to the top of the list
-// TODO: Add a CanSee function for characters and have this function use that to determine if a character can see another character in the who list
 func DoWho(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
-	activeCharacters := CharacterMgr.GetOnlineCharacters()
+	q, err := ParseWhoQuery(args)
+	if err != nil {
+		WriteStringF(s, "{{%s}}::red"+CRLF, err.Error())
+		return
+	}
 
-	if len(activeCharacters) == 0 {
-		WriteString(s, cfmt.Sprintf("{{No one else is in the game right now.}}::yellow"+CRLF))
+	var list []*Character
+	for _, activeChar := range CharacterMgr.GetOnlineCharacters() {
+		if char.CanSee(activeChar) && q.Matches(activeChar) {
+			list = append(list, activeChar)
+		}
+	}
+
+	if len(list) == 0 {
+		WriteString(s, cfmt.Sprintf("{{No one matching that is in the game right now.}}::yellow"+CRLF))
 		return
 	}
 
+	idle := func(c *Character) time.Duration {
+		if c.Conn == nil {
+			return 0
+		}
+		return SessionMgr.IdleDuration(c.Conn)
+	}
+	q.SortWho(list, idle)
+
 	WriteString(s, cfmt.Sprintf("{{Players currently in the game:}}::green"+CRLF))
 
-	for _, activeChar := range activeCharacters {
+	for _, activeChar := range list {
 		color := "cyan"
-		if activeChar.Role == CharacterRoleAdmin {
-			color = "yellow"
+		if g := PermissionMgr.GetGroup(activeChar.Role); g != nil && g.Color != "" {
+			color = g.Color
+		}
+
+		title := activeChar.Title
+		if title == "" {
+			title = "the Basic"
+		}
+
+		metatype := ""
+		if m := EntityMgr.GetMetatype(activeChar.MetatypeID); m != nil {
+			metatype = m.Name
+		}
+
+		area := ""
+		if activeChar.Room != nil && activeChar.Room.Area != nil {
+			area = activeChar.Room.Area.Title
+		}
+
+		// Flag teammates and the team leader
+		team := ""
+		if TeamMgr.SameTeam(char, activeChar) {
+			team = " {{[Team]}}::green"
+			if TeamMgr.GetTeam(activeChar).IsLeader(activeChar) {
+				team = " {{[Team Leader]}}::green"
+			}
+		}
+
+		afk := ""
+		if away, _ := activeChar.IsAFK(); away {
+			afk = " {{[AFK]}}::yellow"
 		}
 
-		if activeChar.Title == "" {
-			activeChar.Title = "the Basic"
+		invis := ""
+		if activeChar.InvisLevel > 0 {
+			invis = cfmt.Sprintf(" {{[Invis %d]}}::magenta", activeChar.InvisLevel)
 		}
 
-		// Display character title and name
-		WriteString(s, cfmt.Sprintf("{{%s - %s}}::%s"+CRLF, activeChar.Name, activeChar.Title, color))
+		WriteString(s, cfmt.Sprintf("{{%-10s}}::white {{%-6s}}::yellow {{%-16s}}::cyan {{%s - %s}}::%s%s%s%s"+CRLF,
+			metatype, FormatIdle(idle(activeChar)), area, activeChar.Name, title, color, team, afk, invis))
 	}
+
+	WriteStringF(s, "{{%d %s shown.}}::green"+CRLF, len(list), pluralizer.PluralizeNoun("player", len(list)))
 }
 
 func DoPrompt(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
@@ -300,7 +362,7 @@ func DoPrompt(s ssh.Session, cmd string, args []string, user *Account, char *Cha
 	if len(args) == 0 {
 		WriteStringF(s, "{{Your current prompt:}}::cyan %s"+CRLF, char.Prompt)
 		WriteString(s, "{{Use 'prompt <new format>' to set a custom prompt.}}::yellow"+CRLF)
-		WriteString(s, "{{Available Macros:}}::green {{time}}, {{hp}}, {{gold}}, {{stamina}} "+CRLF)
+		WriteString(s, "{{Available Macros:}}::green {{time}}, {{date}}, {{weather}} "+CRLF)
 		return
 	}
 
@@ -367,6 +429,137 @@ func DoTime(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	}
 }
 
+/*
+Usage:
+  - search
+*/
+func DoSearch(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s searches the room.}}::green"+CRLF, char.Name), []string{char.ID})
+
+	found := char.SearchForExits(room, false)
+	if len(found) == 0 {
+		WriteString(s, "{{You search the room but find nothing.}}::yellow"+CRLF)
+		return
+	}
+
+	for _, dir := range found {
+		WriteStringF(s, "{{You find a hidden exit to the %s!}}::green"+CRLF, dir)
+	}
+}
+
+/*
+Usage:
+  - map
+  - map <radius>
+  - map auto
+*/
+func DoMap(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	radius := MapRadius
+
+	switch {
+	case len(args) == 0:
+	case len(args) == 1 && strings.EqualFold(args[0], "auto"):
+		char.AutoMap = !char.AutoMap
+		char.Save()
+
+		if char.AutoMap {
+			WriteString(s, "{{The map will be shown beside room descriptions.}}::green"+CRLF)
+		} else {
+			WriteString(s, "{{The map will no longer be shown beside room descriptions.}}::green"+CRLF)
+		}
+		return
+	case len(args) == 1:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > MapMaxRadius {
+			WriteStringF(s, "{{The map radius must be between 1 and %d.}}::red"+CRLF, MapMaxRadius)
+			return
+		}
+		radius = n
+	default:
+		WriteString(s, "{{Usage: map [radius] | map auto}}::yellow"+CRLF)
+		return
+	}
+
+	if !char.CanSeeIn(room) {
+		WriteString(s, "{{It is too dark to see where you are.}}::red"+CRLF)
+		return
+	}
+
+	WriteString(s, RenderMap(char, radius))
+}
+
+/*
+Usage:
+  - areas
+  - areas <area_id>
+*/
+func DoAreas(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) > 1 {
+		WriteString(s, "{{Usage: areas [area_id]}}::yellow"+CRLF)
+		return
+	}
+
+	if len(args) == 1 {
+		area := EntityMgr.GetArea(args[0])
+		if area == nil {
+			WriteStringF(s, "{{There is no area %q.}}::red"+CRLF, args[0])
+			return
+		}
+
+		WriteString(s, RenderAreaDetails(area))
+		return
+	}
+
+	areas := make([]*Area, 0, len(EntityMgr.GetAllAreas()))
+	for _, area := range EntityMgr.GetAllAreas() {
+		areas = append(areas, area)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		return strings.ToLower(areas[i].Title) < strings.ToLower(areas[j].Title)
+	})
+
+	WriteString(s, "{{Areas of the world:}}::green"+CRLF)
+	for _, area := range areas {
+		WriteString(s, cfmt.Sprintf("{{%-25s}}::white|bold {{%-10s}}::cyan {{%-9s}}::yellow %s"+CRLF,
+			area.Title, area.KarmaRange(), area.ThreatLevel, strings.Join(area.Builders, ", ")))
+	}
+}
+
+// RenderAreaDetails describes an area for the areas command.
+func RenderAreaDetails(area *Area) string {
+	karma := area.KarmaRange()
+
+	area.RLock()
+	defer area.RUnlock()
+
+	var sb strings.Builder
+	sb.WriteString(cfmt.Sprintf("{{%s}}::white|bold {{(%s)}}::cyan"+CRLF, area.Title, area.ID))
+	if area.Description != "" {
+		sb.WriteString(area.Description + CRLF)
+	}
+	if len(area.Builders) > 0 {
+		sb.WriteString(cfmt.Sprintf("{{Builders:}}::green %s"+CRLF, strings.Join(area.Builders, ", ")))
+	}
+	if karma != "" {
+		sb.WriteString(cfmt.Sprintf("{{Recommended karma:}}::green %s"+CRLF, karma))
+	}
+	if area.ThreatLevel != "" {
+		sb.WriteString(cfmt.Sprintf("{{Threat level:}}::green %s"+CRLF, area.ThreatLevel))
+	}
+	if len(area.Flags) > 0 {
+		sb.WriteString(cfmt.Sprintf("{{Flags:}}::green %s"+CRLF, strings.Join(area.Flags, ", ")))
+	}
+	if area.ResetInterval > 0 {
+		sb.WriteString(cfmt.Sprintf("{{Resets every:}}::green %s"+CRLF, area.ResetInterval))
+	}
+	if len(area.Weather) > 0 {
+		effect := GetWeatherEffect(area.weather)
+		sb.WriteString(cfmt.Sprintf("{{Weather:}}::green {{%s}}::%s"+CRLF, effect.Name, effect.Color))
+	}
+
+	return sb.String()
+}
+
 func DoHistory(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
 	if len(char.CommandHistory) == 0 {
 		WriteString(s, "{{No command history available.}}::yellow"+CRLF)
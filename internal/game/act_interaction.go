@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -23,7 +24,7 @@ func DoLock(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		return
 	}
 
-	exit, exists := room.Exits[direction]
+	exit, exists := room.GetVisibleExit(char, direction)
 	if !exists {
 		WriteStringF(s, "{{There is no exit to the %s.}}::red"+CRLF, direction)
 		return
@@ -44,28 +45,14 @@ func DoLock(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		return
 	}
 
-	validKeys := make(map[string]bool)
-	for _, key := range exit.Door.KeyIDs {
-		validKeys[key] = true
-	}
-
-	hasKey := false
-	for _, item := range char.Inventory.Items {
-		bp := EntityMgr.GetItemBlueprintByInstance(item)
-		if bp.Type == ItemTypeKey && validKeys[bp.ID] {
-			hasKey = true
-			break
-		}
-	}
-
-	if !hasKey {
+	if !char.HasKeyFor(room, exit.Door) {
 		WriteStringF(s, "{{You don't have the key to lock the door to the %s.}}::red"+CRLF, direction)
 		return
 	}
 
 	exit.Door.IsLocked = true
 	WriteStringF(s, "{{You lock the door to the %s.}}::green"+CRLF, direction)
-	room.Broadcast(cfmt.Sprintf("{{%s locks the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s locks the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
 }
 
 func DoUnlock(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
@@ -75,7 +62,7 @@ func DoUnlock(s ssh.Session, cmd string, args []string, user *Account, char *Cha
 	}
 
 	direction := ParseDirection(args[0])
-	exit, exists := room.Exits[direction]
+	exit, exists := room.GetVisibleExit(char, direction)
 	if !exists {
 		WriteStringF(s, "{{There is no exit to the %s.}}::red"+CRLF, direction)
 		return
@@ -91,29 +78,15 @@ func DoUnlock(s ssh.Session, cmd string, args []string, user *Account, char *Cha
 		return
 	}
 
-	validKeys := make(map[string]bool)
-	for _, key := range exit.Door.KeyIDs {
-		validKeys[key] = true
-	}
-
 	// Check if character has the correct key
-	hasKey := false
-	for _, item := range char.Inventory.Items {
-		bp := EntityMgr.GetItemBlueprintByInstance(item)
-		if bp.Type == ItemTypeKey && validKeys[bp.ID] {
-			hasKey = true
-			break
-		}
-	}
-
-	if !hasKey {
+	if !char.HasKeyFor(room, exit.Door) {
 		WriteStringF(s, "{{You don't have the key to unlock the door to the %s.}}::red"+CRLF, direction)
 		return
 	}
 
 	exit.Door.IsLocked = false
 	WriteStringF(s, "{{You unlock the door to the %s.}}::green"+CRLF, direction)
-	room.Broadcast(cfmt.Sprintf("{{%s unlocks the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s unlocks the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
 }
 
 func DoPick(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
@@ -123,7 +96,7 @@ func DoPick(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	}
 
 	direction := args[0]
-	exit, exists := room.Exits[direction]
+	exit, exists := room.GetVisibleExit(char, direction)
 	if !exists {
 		WriteStringF(s, "{{There is no exit to the %s.}}::red"+CRLF, direction)
 		return
@@ -161,7 +134,7 @@ func DoPick(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	if pickRoll > exit.Door.PickDifficulty {
 		exit.Door.IsLocked = false
 		WriteStringF(s, "{{You successfully pick the lock on the door to the %s.}}::green"+CRLF, direction)
-		room.Broadcast(cfmt.Sprintf("{{%s picks the lock on the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
+		room.BroadcastAbout(char, cfmt.Sprintf("{{%s picks the lock on the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
 	} else {
 		WriteStringF(s, "{{You fail to pick the lock on the door to the %s.}}::red"+CRLF, direction)
 	}
@@ -175,7 +148,7 @@ func DoOpen(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	}
 
 	direction := ParseDirection(args[0])
-	exit, exists := room.Exits[direction]
+	exit, exists := room.GetVisibleExit(char, direction)
 	if !exists {
 		WriteStringF(s, "{{There is no exit to the %s.}}::red"+CRLF, direction)
 		return
@@ -198,7 +171,7 @@ func DoOpen(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 
 	exit.Door.IsClosed = false
 	WriteStringF(s, "{{You open the door to the %s.}}::green"+CRLF, direction)
-	room.Broadcast(cfmt.Sprintf("{{%s opens the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s opens the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
 
 	// Notify the adjacent room
 	if exit.Room != nil {
@@ -214,7 +187,7 @@ func DoClose(s ssh.Session, cmd string, args []string, user *Account, char *Char
 
 	direction := ParseDirection(args[0])
 
-	exit, exists := room.Exits[direction]
+	exit, exists := room.GetVisibleExit(char, direction)
 	if !exists {
 		WriteStringF(s, "{{There is no exit to the %s.}}::red"+CRLF, direction)
 		return
@@ -232,7 +205,7 @@ func DoClose(s ssh.Session, cmd string, args []string, user *Account, char *Char
 
 	exit.Door.IsClosed = true
 	WriteStringF(s, "{{You close the door to the %s.}}::green"+CRLF, direction)
-	room.Broadcast(cfmt.Sprintf("{{%s closes the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s closes the door to the %s.}}::green"+CRLF, char.Name, direction), []string{char.ID})
 
 	// Notify the adjacent room
 	if exit.Room != nil {
@@ -275,18 +248,21 @@ func DoDrop(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		}
 
 		droppedItems := make(map[string]int)
+		var transferred []*ItemInstance
 
 		for i := 0; i < len(char.Inventory.Items); {
 			item := char.Inventory.Items[i]
 			blueprint := EntityMgr.GetItemBlueprintByInstance(item)
 			droppedItems[blueprint.Name]++
+			transferred = append(transferred, item)
 			room.Inventory.Add(item)
 			char.Inventory.Remove(item)
 		}
+		AuditMgr.LogTransfer(char, cmd, room.ID, transferred, room)
 
 		for itemName, count := range droppedItems {
 			WriteStringF(s, "{{You drop %d %s.}}::green"+CRLF, count, pluralizer.PluralizeNoun(itemName, count))
-			room.Broadcast(cfmt.Sprintf("{{%s drops %d %s.}}::green"+CRLF, char.Name, count, pluralizer.PluralizeNoun(itemName, count)), []string{char.ID})
+			room.BroadcastAbout(char, cfmt.Sprintf("{{%s drops %d %s.}}::green"+CRLF, char.Name, count, pluralizer.PluralizeNoun(itemName, count)), []string{char.ID})
 		}
 		return
 	}
@@ -296,6 +272,7 @@ func DoDrop(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		prefix := strings.ToLower(strings.TrimPrefix(strings.ToLower(itemQuery), "all "))
 		found := false
 		droppedItems := make(map[string]int)
+		var transferred []*ItemInstance
 
 		for i := 0; i < len(char.Inventory.Items); {
 			item := char.Inventory.Items[i]
@@ -306,14 +283,16 @@ func DoDrop(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 			}
 
 			droppedItems[blueprint.Name]++
+			transferred = append(transferred, item)
 			room.Inventory.Add(item)
 			char.Inventory.Remove(item)
 			found = true
 		}
+		AuditMgr.LogTransfer(char, cmd, room.ID, transferred, room)
 
 		for itemName, count := range droppedItems {
 			WriteStringF(s, "{{You drop %d %s.}}::green"+CRLF, count, pluralizer.PluralizeNoun(itemName, count))
-			room.Broadcast(cfmt.Sprintf("{{%s drops %d %s.}}::green"+CRLF, char.Name, count, pluralizer.PluralizeNoun(itemName, count)), []string{char.ID})
+			room.BroadcastAbout(char, cfmt.Sprintf("{{%s drops %d %s.}}::green"+CRLF, char.Name, count, pluralizer.PluralizeNoun(itemName, count)), []string{char.ID})
 		}
 
 		if !found {
@@ -342,10 +321,11 @@ func DoDrop(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 		room.Inventory.Add(item)
 		char.Inventory.Remove(item)
 	}
+	AuditMgr.LogTransfer(char, cmd, room.ID, items[:quantity], room)
 
 	for itemName, count := range droppedItems {
 		WriteStringF(s, "{{You drop %d %s.}}::green"+CRLF, count, pluralizer.PluralizeNoun(itemName, count))
-		room.Broadcast(cfmt.Sprintf("{{%s drops %d %s.}}::green"+CRLF, char.Name, count, pluralizer.PluralizeNoun(itemName, count)), []string{char.ID})
+		room.BroadcastAbout(char, cfmt.Sprintf("{{%s drops %d %s.}}::green"+CRLF, char.Name, count, pluralizer.PluralizeNoun(itemName, count)), []string{char.ID})
 	}
 }
 
@@ -386,7 +366,7 @@ func DoGive(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 	recipientName := args[0]
 	var recipient *Character
 	for _, r := range room.Characters {
-		if strings.EqualFold(r.Name, recipientName) {
+		if strings.EqualFold(r.Name, recipientName) && char.CanSee(r) {
 			recipient = r
 			break
 		}
@@ -428,6 +408,10 @@ func DoGive(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 
 	for _, item := range matchingItems[:quantity] {
 		bp := EntityMgr.GetItemBlueprintByInstance(item)
+		if bp != nil && bp.Type == ItemTypeBoard {
+			// Boards are fixtures of the room
+			continue
+		}
 		if bp != nil && bp.Weight <= remainingCapacity {
 			totalWeight += bp.Weight
 			if totalWeight > remainingCapacity {
@@ -446,6 +430,7 @@ func DoGive(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 
 	char.Save()
 	recipient.Save()
+	AuditMgr.LogTransfer(char, cmd, recipient.Name, givenItems, room)
 
 	if len(givenItems) == 0 {
 		WriteStringF(s, "{{%s cannot carry any more weight.}}::yellow"+CRLF, recipient.Name)
@@ -454,7 +439,7 @@ func DoGive(s ssh.Session, cmd string, args []string, user *Account, char *Chara
 
 	itemName := pluralizer.PluralizeNoun(EntityMgr.GetItemBlueprintByInstance(givenItems[0]).Name, len(givenItems))
 	WriteStringF(s, "{{You give %s %d %s.}}::green"+CRLF, recipient.Name, len(givenItems), itemName)
-	room.Broadcast(cfmt.Sprintf("{{%s gives %s %d %s.}}::green"+CRLF, char.Name, recipient.Name, len(givenItems), itemName), []string{char.ID, recipient.ID})
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s gives %s %d %s.}}::green"+CRLF, char.Name, recipient.Name, len(givenItems), itemName), []string{char.ID, recipient.ID})
 
 	if len(givenItems) < quantity {
 		WriteStringF(s, "{{%s could not take all items due to weight limits.}}::yellow"+CRLF, recipient.Name)
@@ -467,7 +452,7 @@ func SuggestGive(line string, args []string, char *Character, room *Room) []stri
 	switch len(args) {
 	case 0: // Suggest character names for the first argument
 		for _, r := range room.Characters {
-			if !strings.EqualFold(r.Name, char.Name) { // Exclude self
+			if !strings.EqualFold(r.Name, char.Name) && char.CanSee(r) { // Exclude self
 				suggestions = append(suggestions, r.Name)
 			}
 		}
@@ -536,6 +521,21 @@ func DoGet(s ssh.Session, cmd string, args []string, user *Account, char *Charac
 	singularQuery := Singularize(itemQuery)
 	matchingItems := room.Inventory.Search(singularQuery)
 
+	// Boards are fixtures of the room
+	var fixtures []*ItemInstance
+	matchingItems = slices.DeleteFunc(matchingItems, func(item *ItemInstance) bool {
+		bp := EntityMgr.GetItemBlueprintByInstance(item)
+		if bp != nil && bp.Type == ItemTypeBoard {
+			fixtures = append(fixtures, item)
+			return true
+		}
+		return false
+	})
+	if len(matchingItems) == 0 && len(fixtures) > 0 && itemQuery != "all" {
+		WriteStringF(s, "{{%s is fixed in place.}}::yellow"+CRLF, Capitalize(EntityMgr.GetItemBlueprintByInstance(fixtures[0]).Name))
+		return
+	}
+
 	// If no items match the query, inform the user
 	if len(matchingItems) == 0 {
 		if itemQuery == "all" {
@@ -587,6 +587,8 @@ func DoGet(s ssh.Session, cmd string, args []string, user *Account, char *Charac
 		char.Inventory.Add(item)
 	}
 
+	AuditMgr.LogTransfer(char, cmd, room.ID, pickedItems, room)
+
 	// Remove picked items from `matchingItems` to update for subsequent "get all" commands
 	matchingItems = matchingItems[len(pickedItems):]
 
@@ -595,7 +597,7 @@ func DoGet(s ssh.Session, cmd string, args []string, user *Account, char *Charac
 	// Inform the user about the items they successfully picked up
 	itemName := pluralizer.PluralizeNoun(EntityMgr.GetItemBlueprintByInstance(pickedItems[0]).Name, len(pickedItems))
 	WriteStringF(s, "{{You get %d %s.}}::green"+CRLF, len(pickedItems), itemName)
-	room.Broadcast(cfmt.Sprintf("{{%s picks up %d %s.}}::green"+CRLF, char.Name, len(pickedItems), itemName), []string{char.ID})
+	room.BroadcastAbout(char, cfmt.Sprintf("{{%s picks up %d %s.}}::green"+CRLF, char.Name, len(pickedItems), itemName), []string{char.ID})
 
 	// Additional feedback for partial pickups
 	if itemQuery == "all" && len(matchingItems) > 0 {
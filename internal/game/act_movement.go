@@ -1,7 +1,12 @@
 package game
 
 import (
+	"errors"
+	"strings"
+
+	"github.com/Jasrags/NewMUD/pluralizer"
 	"github.com/gliderlabs/ssh"
+	"github.com/i582/cfmt/cmd/cfmt"
 )
 
 /*
@@ -10,27 +15,156 @@ Usage:
   - <north,n,south,s,east,e,west,w,up,u,down,d>
 */
 func DoMove(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
-	if cmd == "move" && len(args) == 0 {
-		WriteString(s, "{{Move where?}}::red"+CRLF)
+	dir := ParseDirection(cmd)
+	if dir == "" {
+		if len(args) == 0 {
+			WriteString(s, "{{Move where?}}::red"+CRLF)
+			return
+		}
+		dir = ParseDirection(strings.ToLower(args[0]))
+	}
+
+	if moveCharacter(s, char, dir) {
+		WriteString(s, RenderRoom(user, char, nil))
+		noticeHiddenExits(s, char)
+	}
+}
+
+/*
+Usage:
+  - run <speedwalk>
+  - run 3n2e
+*/
+func DoRun(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		WriteString(s, "{{Usage: run <speedwalk>, such as run 3n2e}}::yellow"+CRLF)
 		return
 	}
 
-	dir := ParseDirection(cmd)
+	dirs, err := ParseSpeedwalk(strings.Join(args, ""))
+	if err != nil {
+		WriteStringF(s, "{{%s}}::red"+CRLF, Capitalize(err.Error()))
+		return
+	}
 
-	// Check if the exit exists
-	if exit, ok := char.Room.Exits[dir]; ok {
-		if exit.Door != nil && exit.Door.IsClosed {
-			WriteStringF(s, "{{The door to the %s is closed.}}::red"+CRLF, dir)
-			return
+	moved := 0
+	for _, dir := range dirs {
+		if !moveCharacter(s, char, dir) {
+			if moved > 0 {
+				WriteString(s, "{{You stop running.}}::yellow"+CRLF)
+			}
+			break
 		}
+		moved++
+	}
 
-		char.MoveToRoom(exit.Room)
-		char.Save()
-
-		WriteStringF(s, "You move %s."+CRLF, dir)
+	if moved > 0 {
 		WriteString(s, RenderRoom(user, char, nil))
+		noticeHiddenExits(s, char)
+	}
+}
+
+/*
+Usage:
+  - path <room_id>
+  - path <landmark>
+*/
+func DoPath(s ssh.Session, cmd string, args []string, user *Account, char *Character, room *Room) {
+	if len(args) == 0 {
+		WriteString(s, "{{Usage: path <room|landmark>}}::yellow"+CRLF)
+		return
+	}
+
+	opts := PathOptions{Character: char, OpenDoors: true}
+	target := strings.Join(args, " ")
+
+	var steps []PathStep
+	var err error
+	if to := EntityMgr.ResolveRoom(room.AreaID, target); to != nil {
+		steps, err = FindPath(room, to, opts)
 	} else {
+		steps, err = FindLandmark(room, target, opts)
+	}
+
+	switch {
+	case errors.Is(err, ErrNoPath):
+		WriteStringF(s, "{{You don't know a way to %s from here.}}::red"+CRLF, target)
+		return
+	case len(steps) == 0:
+		WriteString(s, "{{You're already there.}}::green"+CRLF)
+		return
+	}
+
+	to := steps[len(steps)-1].Room
+	WriteStringF(s, "{{The way to %s is %d %s:}}::green {{%s}}::yellow|bold"+CRLF,
+		to.Title, len(steps), pluralizer.PluralizeNoun("step", len(steps)), FormatSpeedwalk(steps))
+
+	// Doors on the way have to be opened by hand
+	prev := room
+	for _, step := range steps {
+		if exit := prev.Exits[step.Direction]; exit.Door != nil && exit.Door.IsClosed {
+			WriteStringF(s, "{{There is a closed door %s of %s.}}::yellow"+CRLF, step.Direction, prev.Title)
+		}
+		prev = step.Room
+	}
+}
+
+// moveCharacter moves the character through the exit in a direction, reporting whether they moved.
+func moveCharacter(s ssh.Session, char *Character, dir string) bool {
+	exit, ok := char.Room.GetVisibleExit(char, dir)
+	if !ok || exit.Room == nil {
 		WriteString(s, "{{You can't go that way.}}::red"+CRLF)
+		return false
+	}
+
+	if exit.Door != nil && exit.Door.IsClosed {
+		WriteStringF(s, "{{The door to the %s is closed.}}::red"+CRLF, dir)
+		return false
+	}
+
+	if err := exit.Traverse(char); err != nil {
+		WriteStringF(s, "{{%s}}::red"+CRLF, err.Error())
+		return false
+	}
+
+	prevRoom := char.Room
+	char.MoveToRoom(exit.Room)
+	char.Save()
+
+	WriteStringF(s, "You move %s."+CRLF, dir)
+
+	followLeader(char, prevRoom, dir)
+
+	return true
+}
+
+// followLeader moves the teammates that were in the same room as their leader along with them.
+func followLeader(leader *Character, prevRoom *Room, dir string) {
+	t := TeamMgr.GetTeam(leader)
+	if t == nil || !t.IsLeader(leader) {
 		return
 	}
+
+	for _, member := range t.GetMembers() {
+		if member == leader || member.Conn == nil || member.Room != prevRoom {
+			continue
+		}
+		if err := prevRoom.Exits[dir].Traverse(member); err != nil {
+			member.Send(cfmt.Sprintf("{{You can't follow %s %s. %s}}::red"+CRLF, leader.Name, dir, err.Error()))
+			continue
+		}
+
+		member.Send(cfmt.Sprintf("{{You follow %s %s.}}::green"+CRLF, leader.Name, dir))
+		member.MoveToRoom(leader.Room)
+		member.Save()
+		member.Send(RenderRoom(member.Account, member, nil))
+		noticeHiddenExits(member.Conn, member)
+	}
+}
+
+// noticeHiddenExits gives the character a passive Perception test to notice the hidden exits in their room.
+func noticeHiddenExits(s ssh.Session, char *Character) {
+	for _, dir := range char.SearchForExits(char.Room, true) {
+		WriteStringF(s, "{{You notice a hidden exit to the %s!}}::cyan"+CRLF, dir)
+	}
 }
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	AreaIDSeparator = ":"
+)
+
+var (
+	ErrReferenceNotFound  = errors.New("not found")
+	ErrReferenceAmbiguous = errors.New("defined in more than one area, qualify it with the area (area:id)")
+)
+
+type (
+	// areaIndex stores entities by their area-qualified ID ("limbo:small_rock") so areas can reuse IDs, and
+	// finds them by a qualified ID or by a bare ID that only one area uses.
+	areaIndex[T any] struct {
+		entries map[string]T
+		bare    map[string][]string // bare ID to the qualified IDs using it
+	}
+)
+
+// QualifyID returns the area-qualified form of an ID, or the ID itself when it isn't part of an area.
+func QualifyID(areaID, id string) string {
+	id = strings.ToLower(id)
+	if areaID == "" || strings.Contains(id, AreaIDSeparator) {
+		return id
+	}
+
+	return strings.ToLower(areaID) + AreaIDSeparator + id
+}
+
+// SplitQualifiedID splits a reference into its area and ID. The area is empty for bare IDs.
+func SplitQualifiedID(ref string) (string, string) {
+	ref = strings.ToLower(ref)
+	if areaID, id, ok := strings.Cut(ref, AreaIDSeparator); ok {
+		return areaID, id
+	}
+
+	return "", ref
+}
+
+func newAreaIndex[T any]() *areaIndex[T] {
+	return &areaIndex[T]{
+		entries: make(map[string]T),
+		bare:    make(map[string][]string),
+	}
+}
+
+// add stores the entity, reporting false if the area already has an entity with the ID.
+func (idx *areaIndex[T]) add(areaID, id string, v T) bool {
+	key := QualifyID(areaID, id)
+	if _, ok := idx.entries[key]; ok {
+		return false
+	}
+
+	idx.entries[key] = v
+	_, bareID := SplitQualifiedID(key)
+	idx.bare[bareID] = append(idx.bare[bareID], key)
+
+	return true
+}
+
+func (idx *areaIndex[T]) remove(areaID, id string) {
+	key := QualifyID(areaID, id)
+	if _, ok := idx.entries[key]; !ok {
+		return
+	}
+
+	delete(idx.entries, key)
+	_, bareID := SplitQualifiedID(key)
+	keys := idx.bare[bareID]
+	for i, k := range keys {
+		if k == key {
+			idx.bare[bareID] = append(keys[:i:i], keys[i+1:]...)
+			break
+		}
+	}
+	if len(idx.bare[bareID]) == 0 {
+		delete(idx.bare, bareID)
+	}
+}
+
+// lookup finds the entity a reference made from within an area points to. Qualified references name their
+// area, while bare ones prefer the area they're made from and otherwise have to be used by a single area.
+func (idx *areaIndex[T]) lookup(areaID, ref string) (T, error) {
+	var zero T
+
+	refArea, id := SplitQualifiedID(ref)
+	if refArea != "" {
+		if v, ok := idx.entries[QualifyID(refArea, id)]; ok {
+			return v, nil
+		}
+		return zero, ErrReferenceNotFound
+	}
+
+	if areaID != "" {
+		if v, ok := idx.entries[QualifyID(areaID, id)]; ok {
+			return v, nil
+		}
+	}
+
+	switch keys := idx.bare[id]; len(keys) {
+	case 0:
+		return zero, ErrReferenceNotFound
+	case 1:
+		return idx.entries[keys[0]], nil
+	default:
+		return zero, ErrReferenceAmbiguous
+	}
+}
+
+// areas returns the qualified IDs using the bare ID.
+func (idx *areaIndex[T]) areas(id string) []string {
+	return idx.bare[strings.ToLower(id)]
+}
+
+func (idx *areaIndex[T]) all() map[string]T {
+	return idx.entries
+}
+
+func (idx *areaIndex[T]) len() int {
+	return len(idx.entries)
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQualifyID(t *testing.T) {
+	assert.Equal(t, "limbo:small_rock", QualifyID("limbo", "small_rock"))
+	assert.Equal(t, "limbo:small_rock", QualifyID("Limbo", "Small_Rock"))
+	assert.Equal(t, "seattle:small_rock", QualifyID("limbo", "seattle:small_rock"))
+	assert.Equal(t, "small_rock", QualifyID("", "small_rock"))
+
+	areaID, id := SplitQualifiedID("limbo:small_rock")
+	assert.Equal(t, "limbo", areaID)
+	assert.Equal(t, "small_rock", id)
+
+	areaID, id = SplitQualifiedID("small_rock")
+	assert.Empty(t, areaID)
+	assert.Equal(t, "small_rock", id)
+}
+
+func TestAreaIndexLookup(t *testing.T) {
+	idx := newAreaIndex[string]()
+	assert.True(t, idx.add("limbo", "small_rock", "limbo rock"))
+	assert.True(t, idx.add("seattle", "small_rock", "seattle rock"))
+	assert.True(t, idx.add("seattle", "stuffer_shack", "shack"))
+	assert.False(t, idx.add("limbo", "small_rock", "another rock"), "duplicates within an area are rejected")
+	assert.Equal(t, 3, idx.len())
+
+	v, err := idx.lookup("", "limbo:small_rock")
+	assert.NoError(t, err)
+	assert.Equal(t, "limbo rock", v)
+
+	v, err = idx.lookup("seattle", "small_rock")
+	assert.NoError(t, err)
+	assert.Equal(t, "seattle rock", v, "bare references prefer the owning area")
+
+	v, err = idx.lookup("limbo", "stuffer_shack")
+	assert.NoError(t, err)
+	assert.Equal(t, "shack", v, "bare references used by one area resolve from anywhere")
+
+	_, err = idx.lookup("", "small_rock")
+	assert.ErrorIs(t, err, ErrReferenceAmbiguous)
+
+	_, err = idx.lookup("seattle", "limbo:stuffer_shack")
+	assert.ErrorIs(t, err, ErrReferenceNotFound)
+
+	idx.remove("limbo", "small_rock")
+	v, err = idx.lookup("", "small_rock")
+	assert.NoError(t, err)
+	assert.Equal(t, "seattle rock", v)
+	assert.Equal(t, []string{"seattle:small_rock"}, idx.areas("small_rock"))
+}
@@ -2,24 +2,68 @@ package game
 
 import (
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+
+	"golang.org/x/exp/rand"
 
 	ee "github.com/vansante/go-event-emitter"
 )
 
 const (
-	AreasFilepath = "_data/areas"
-	AreasFilename = "manifest.yml"
+	AreasFilepath   = "_data/areas"
+	AreasFilename   = "manifest.yml"
+	AreaReloadDelay = 500 * time.Millisecond
+)
+
+// Area flags. There's no combat, magic or recall yet, so no_combat, no_magic and no_recall are accepted
+// and reported by HasFlag but don't do anything until those are added. Safe areas are already spared
+// environmental hazards.
+const (
+	AreaFlagNoCombat = "no_combat" // Fighting isn't allowed, not enforced yet
+	AreaFlagNoMagic  = "no_magic"  // Spells and other magic don't work, not enforced yet
+	AreaFlagNoRecall = "no_recall" // Recall can't be used to leave, not enforced yet
+	AreaFlagSafe     = "safe"      // Nothing can be harmed, implies no_combat
+)
+
+const (
+	ThreatLevelLow      = "low"
+	ThreatLevelModerate = "moderate"
+	ThreatLevelHigh     = "high"
+	ThreatLevelDeadly   = "deadly"
+)
+
+var (
+	AreaFlags    = []string{AreaFlagNoCombat, AreaFlagNoMagic, AreaFlagNoRecall, AreaFlagSafe}
+	ThreatLevels = []string{ThreatLevelLow, ThreatLevelModerate, ThreatLevelHigh, ThreatLevelDeadly}
 )
 
 type (
 	Area struct {
-		sync.RWMutex
-		Listeners []ee.Listener `yaml:"-"`
+		sync.RWMutex `yaml:"-"`
+		Listeners    []ee.Listener `yaml:"-"`
 
-		ID          string `yaml:"id"`
-		Title       string `yaml:"title"`
-		Description string `yaml:"description"`
+		ID              string        `yaml:"id"`
+		Title           string        `yaml:"title"`
+		Description     string        `yaml:"description"`
+		Builders        []string      `yaml:"builders,omitempty"`
+		MinKarma        int           `yaml:"min_karma,omitempty"`    // Recommended karma earned before visiting
+		MaxKarma        int           `yaml:"max_karma,omitempty"`    // Karma past which the area stops being a challenge
+		ThreatLevel     string        `yaml:"threat_level,omitempty"` // One of ThreatLevels
+		ResetInterval   time.Duration `yaml:"reset_interval,omitempty"`
+		Flags           []string      `yaml:"flags,omitempty"`
+		RoomTags        []string      `yaml:"room_tags,omitempty"` // Tags every room in the area has
+		AmbientMessages []string      `yaml:"ambient_messages,omitempty"`
+		AmbientInterval time.Duration `yaml:"ambient_interval,omitempty"`
+		Weather         []string      `yaml:"weather,omitempty"` // Weathers the area can have, repeated to make one more likely
+		Dir             string        `yaml:"-"`                 // Directory the area was loaded from
+
+		lastReset   time.Time
+		lastAmbient time.Time
+		weather     string
 	}
 )
 
@@ -31,3 +75,92 @@ func (a *Area) Init() {
 	slog.Debug("Initializing area",
 		slog.String("area_id", a.ID))
 }
+
+// Refresh copies the data loaded from the area's manifest into the area.
+func (a *Area) Refresh(from *Area) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.Title = from.Title
+	a.Description = from.Description
+	a.Builders = from.Builders
+	a.MinKarma = from.MinKarma
+	a.MaxKarma = from.MaxKarma
+	a.ThreatLevel = from.ThreatLevel
+	a.ResetInterval = from.ResetInterval
+	a.Flags = from.Flags
+	a.RoomTags = from.RoomTags
+	a.AmbientMessages = from.AmbientMessages
+	a.AmbientInterval = from.AmbientInterval
+	a.Weather = from.Weather
+	a.Dir = from.Dir
+}
+
+// HasFlag reports whether the area has the flag. Safe areas are also no-combat areas.
+func (a *Area) HasFlag(flag string) bool {
+	a.RLock()
+	defer a.RUnlock()
+
+	if flag == AreaFlagNoCombat && slices.ContainsFunc(a.Flags, func(f string) bool { return strings.EqualFold(f, AreaFlagSafe) }) {
+		return true
+	}
+
+	return slices.ContainsFunc(a.Flags, func(f string) bool { return strings.EqualFold(f, flag) })
+}
+
+// KarmaRange describes the recommended karma for the area, or is empty if it has none.
+func (a *Area) KarmaRange() string {
+	a.RLock()
+	defer a.RUnlock()
+
+	switch {
+	case a.MinKarma == 0 && a.MaxKarma == 0:
+		return ""
+	case a.MaxKarma == 0:
+		return strconv.Itoa(a.MinKarma) + "+"
+	default:
+		return strconv.Itoa(a.MinKarma) + "-" + strconv.Itoa(a.MaxKarma)
+	}
+}
+
+// ResetDue reports whether the area's reset interval has passed since it last reset, marking it as reset
+// if so.
+func (a *Area) ResetDue(now time.Time) bool {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.ResetInterval <= 0 {
+		return false
+	}
+	if a.lastReset.IsZero() {
+		a.lastReset = now
+		return false
+	}
+	if now.Sub(a.lastReset) < a.ResetInterval {
+		return false
+	}
+
+	a.lastReset = now
+	return true
+}
+
+// NextAmbientMessage returns a random ambient message once the ambient interval has passed since the
+// last one, or an empty string if it isn't time for one yet.
+func (a *Area) NextAmbientMessage(now time.Time) string {
+	a.Lock()
+	defer a.Unlock()
+
+	if len(a.AmbientMessages) == 0 || a.AmbientInterval <= 0 {
+		return ""
+	}
+	if a.lastAmbient.IsZero() {
+		a.lastAmbient = now
+		return ""
+	}
+	if now.Sub(a.lastAmbient) < a.AmbientInterval {
+		return ""
+	}
+
+	a.lastAmbient = now
+	return a.AmbientMessages[rand.Intn(len(a.AmbientMessages))]
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAreaManifestMetadata(t *testing.T) {
+	var area Area
+	err := yaml.Unmarshal([]byte(`id: seattle
+builders: [Jasrags]
+min_karma: 10
+max_karma: 50
+threat_level: moderate
+reset_interval: 30m
+flags: [safe, no_magic]
+room_tags: [Street]
+ambient_messages: ["A car alarm wails."]
+ambient_interval: 10m
+`), &area)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"Jasrags"}, area.Builders)
+	assert.Equal(t, "10-50", area.KarmaRange())
+	assert.Equal(t, 30*time.Minute, area.ResetInterval)
+	assert.Equal(t, 10*time.Minute, area.AmbientInterval)
+	assert.True(t, area.HasFlag(AreaFlagNoMagic))
+	assert.True(t, area.HasFlag(AreaFlagNoCombat), "safe areas are no-combat areas")
+	assert.False(t, area.HasFlag(AreaFlagNoRecall))
+
+	room := &Room{Tags: []string{"Bar", "street"}, Area: &area}
+	assert.Equal(t, []string{"Bar", "street"}, room.AllTags(), "area tags the room already has aren't repeated")
+	room.Tags = []string{"Bar"}
+	assert.Equal(t, []string{"Bar", "Street"}, room.AllTags())
+	assert.True(t, room.HasFlag(AreaFlagSafe))
+
+	// The room header shows the area's tags too
+	withTestEntityManager(t)
+	withTestGameTime(t, 12)
+	EntityMgr.AddMetatype(&Metatype{ID: "human", Name: "Human"})
+	room.Title = "The Bar"
+	room.Characters = make(map[string]*Character)
+	char := newTestCharacter("Bob")
+	char.MetatypeID = "human"
+	char.SetRoom(room)
+	room.AddCharacter(char)
+	assert.Contains(t, stripANSI(RenderRoom(nil, char, nil)), "[Bar, Street]")
+}
+
+func TestAreaTimers(t *testing.T) {
+	area := &Area{ResetInterval: time.Minute, AmbientMessages: []string{"A car alarm wails."}, AmbientInterval: time.Minute}
+	now := time.Now()
+
+	assert.False(t, area.ResetDue(now), "the first check starts the timer")
+	assert.False(t, area.ResetDue(now.Add(30*time.Second)))
+	assert.True(t, area.ResetDue(now.Add(time.Minute)))
+	assert.False(t, area.ResetDue(now.Add(90*time.Second)))
+
+	assert.Empty(t, area.NextAmbientMessage(now))
+	assert.Equal(t, "A car alarm wails.", area.NextAmbientMessage(now.Add(time.Minute)))
+	assert.Empty(t, area.NextAmbientMessage(now.Add(90*time.Second)))
+
+	assert.Empty(t, (&Area{}).KarmaRange())
+	assert.Equal(t, "25+", (&Area{MinKarma: 25}).KarmaRange())
+}
@@ -0,0 +1,250 @@
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	AuditTypeCommand          = "command"
+	AuditTypeEntityCreated    = "entity_created"
+	AuditTypeTransfer         = "transfer"
+	AuditTypePermissionChange = "permission"
+
+	// Each category of audit entry is written to its own append-only file
+	AuditFileAdmin     = "admin.log"
+	AuditFileTransfers = "transfers.log"
+
+	AuditDefaultQueryLimit = 20
+)
+
+var (
+	AuditMgr = NewAuditManager()
+
+	// AuditTypes are the types of entry that can be queried
+	AuditTypes = []string{AuditTypeCommand, AuditTypeEntityCreated, AuditTypeTransfer, AuditTypePermissionChange}
+
+	auditTypeFiles = map[string]string{
+		AuditTypeCommand:          AuditFileAdmin,
+		AuditTypeEntityCreated:    AuditFileAdmin,
+		AuditTypePermissionChange: AuditFileAdmin,
+		AuditTypeTransfer:         AuditFileTransfers,
+	}
+)
+
+type (
+	AuditEntry struct {
+		Time     time.Time `json:"time"`
+		Type     string    `json:"type"`
+		Actor    string    `json:"actor"`
+		ActorID  string    `json:"actor_id,omitempty"`
+		Command  string    `json:"command,omitempty"`
+		Args     []string  `json:"args,omitempty"`
+		RoomID   string    `json:"room_id,omitempty"`
+		Action   string    `json:"action,omitempty"`
+		Target   string    `json:"target,omitempty"`
+		Entities []string  `json:"entities,omitempty"`
+		Value    int       `json:"value,omitempty"` // Combined cost of the items in a transfer
+	}
+
+	AuditQuery struct {
+		Character string
+		Type      string
+		Limit     int
+	}
+
+	AuditManager struct {
+		sync.Mutex
+	}
+)
+
+func NewAuditManager() *AuditManager {
+	return &AuditManager{}
+}
+
+func (mgr *AuditManager) auditPath() string {
+	return viper.GetString("data.audit_path")
+}
+
+// Log appends the entry to the audit log file for its type.
+func (mgr *AuditManager) Log(entry *AuditEntry) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	dataFilePath := mgr.auditPath()
+	if dataFilePath == "" {
+		return
+	}
+
+	if err := os.MkdirAll(dataFilePath, 0755); err != nil {
+		slog.Error("failed to create audit log directory",
+			slog.Any("error", err))
+		return
+	}
+
+	fileName, ok := auditTypeFiles[entry.Type]
+	if !ok {
+		fileName = AuditFileAdmin
+	}
+	filePath := filepath.Join(dataFilePath, fileName)
+
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		slog.Error("failed to open audit log",
+			slog.String("file", filePath),
+			slog.Any("error", err))
+		return
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		slog.Error("failed to write audit log entry",
+			slog.Any("error", err))
+	}
+}
+
+// LogCommand records the use of a privileged command.
+func (mgr *AuditManager) LogCommand(char *Character, command *Command, args []string, room *Room) {
+	slog.Info("Privileged command",
+		slog.String("character_name", char.Name),
+		slog.String("command", command.Name),
+		slog.Any("args", args))
+
+	mgr.Log(&AuditEntry{
+		Type:    AuditTypeCommand,
+		Actor:   char.Name,
+		ActorID: char.ID,
+		Command: command.Name,
+		Args:    args,
+		RoomID:  auditRoomID(room),
+	})
+}
+
+// LogEntities records entities created by a character, entityType is AuditTypeEntityCreated.
+func (mgr *AuditManager) LogEntities(char *Character, entityType, command string, entities []string, room *Room) {
+	mgr.Log(&AuditEntry{
+		Type:     entityType,
+		Actor:    char.Name,
+		ActorID:  char.ID,
+		Command:  command,
+		RoomID:   auditRoomID(room),
+		Entities: entities,
+	})
+}
+
+// LogPermissionChange records a permission being granted or revoked.
+func (mgr *AuditManager) LogPermissionChange(char *Character, action string, target *Character, permission string) {
+	mgr.Log(&AuditEntry{
+		Type:    AuditTypePermissionChange,
+		Actor:   char.Name,
+		ActorID: char.ID,
+		Action:  action,
+		Target:  target.Name,
+		Args:    []string{permission},
+		RoomID:  auditRoomID(char.Room),
+	})
+}
+
+// LogTransfer records items moving between characters or a character and a room. The value of the
+// transfer is the combined cost of the items.
+func (mgr *AuditManager) LogTransfer(char *Character, action, target string, items []*ItemInstance, room *Room) {
+	if len(items) == 0 {
+		return
+	}
+
+	var entities []string
+	value := 0
+	for _, item := range items {
+		entities = append(entities, item.BlueprintID+"#"+item.InstanceID)
+		if bp := EntityMgr.GetItemBlueprintByInstance(item); bp != nil {
+			value += bp.Cost
+		}
+	}
+
+	mgr.Log(&AuditEntry{
+		Type:     AuditTypeTransfer,
+		Actor:    char.Name,
+		ActorID:  char.ID,
+		Action:   action,
+		Target:   target,
+		RoomID:   auditRoomID(room),
+		Entities: entities,
+		Value:    value,
+	})
+}
+
+func auditRoomID(room *Room) string {
+	if room == nil {
+		return ""
+	}
+
+	return room.ID
+}
+
+// Query returns the most recent entries matching the query, newest first.
+func (mgr *AuditManager) Query(q AuditQuery) []*AuditEntry {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	if q.Limit <= 0 {
+		q.Limit = AuditDefaultQueryLimit
+	}
+
+	var entries []*AuditEntry
+	for _, fileName := range []string{AuditFileAdmin, AuditFileTransfers} {
+		filePath := filepath.Join(mgr.auditPath(), fileName)
+		file, err := os.Open(filePath)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				slog.Error("failed to open audit log",
+					slog.String("file", filePath),
+					slog.Any("error", err))
+			}
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry AuditEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				slog.Warn("Invalid audit log entry",
+					slog.String("file", filePath),
+					slog.Any("error", err))
+				continue
+			}
+
+			if q.Type != "" && !strings.EqualFold(entry.Type, q.Type) {
+				continue
+			}
+			if q.Character != "" && !strings.EqualFold(entry.Actor, q.Character) && !strings.EqualFold(entry.Target, q.Character) {
+				continue
+			}
+
+			entries = append(entries, &entry)
+		}
+		file.Close()
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+
+	if len(entries) > q.Limit {
+		entries = entries[:q.Limit]
+	}
+
+	return entries
+}
@@ -0,0 +1,34 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditManagerQuery(t *testing.T) {
+	viper.Set("data.audit_path", t.TempDir())
+	defer viper.Set("data.audit_path", nil)
+
+	mgr := NewAuditManager()
+	start := time.Now()
+	mgr.Log(&AuditEntry{Time: start, Type: AuditTypeCommand, Actor: "Alice", Command: "spawn", Args: []string{"i", "small_rock"}})
+	mgr.Log(&AuditEntry{Time: start.Add(time.Second), Type: AuditTypeTransfer, Actor: "Bob", Action: "give", Target: "Alice"})
+	mgr.Log(&AuditEntry{Time: start.Add(2 * time.Second), Type: AuditTypeCommand, Actor: "Bob", Command: "goto"})
+
+	entries := mgr.Query(AuditQuery{})
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "goto", entries[0].Command, "newest entries come first")
+
+	entries = mgr.Query(AuditQuery{Character: "alice"})
+	assert.Len(t, entries, 2, "matches both the actor and the target")
+
+	entries = mgr.Query(AuditQuery{Type: AuditTypeTransfer})
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "give", entries[0].Action)
+
+	entries = mgr.Query(AuditQuery{Limit: 1})
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,260 @@
+package game
+
+import (
+	"strings"
+
+	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const (
+	MapRadius     = 3 // Rooms shown in each direction by the map command
+	MapMaxRadius  = 8
+	AutoMapRadius = 2 // Rooms shown in each direction beside room descriptions
+)
+
+const (
+	mapGlyphRoom       = '#'
+	mapGlyphPlayer     = '@'
+	mapGlyphMob        = 'M'
+	mapGlyphUp         = '^'
+	mapGlyphDown       = 'v'
+	mapGlyphUpDown     = 'X'
+	mapGlyphPathNS     = '|'
+	mapGlyphPathEW     = '-'
+	mapGlyphDoorClosed = '+'
+	mapGlyphDoorOpen   = '/'
+)
+
+var (
+	// mapDirections are the directions laid out on the map, in the order they are walked so the layout is
+	// the same every time.
+	mapDirections = []string{"north", "east", "south", "west"}
+	mapOffsets    = map[string]MapPosition{
+		"north": {X: 0, Y: 1},
+		"east":  {X: 1, Y: 0},
+		"south": {X: 0, Y: -1},
+		"west":  {X: -1, Y: 0},
+	}
+)
+
+type (
+	// MapPosition is a room's place on a map relative to the room the map is centered on, with north being
+	// up.
+	MapPosition struct {
+		X int
+		Y int
+	}
+
+	// RoomMap is the rooms around a room on the same level. Rooms with coordinates are placed by them,
+	// the rest by walking the exits from the rooms next to them.
+	RoomMap struct {
+		Origin    *Room
+		Radius    int
+		Rooms     map[MapPosition]*Room
+		Positions map[*Room]MapPosition
+	}
+
+	mapCell struct {
+		glyph rune
+		color string
+	}
+)
+
+// BuildRoomMap maps the rooms within radius steps of the origin, leaving out exits the character hasn't
+// found. A nil character maps every exit.
+func BuildRoomMap(origin *Room, radius int, char *Character) *RoomMap {
+	m := &RoomMap{
+		Origin:    origin,
+		Radius:    radius,
+		Rooms:     map[MapPosition]*Room{{}: origin},
+		Positions: map[*Room]MapPosition{origin: {}},
+	}
+
+	queue := []*Room{origin}
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		pos := m.Positions[room]
+
+		for _, dir := range mapDirections {
+			exit, ok := room.GetVisibleExit(char, dir)
+			if !ok || exit.Room == nil {
+				continue
+			}
+			if _, ok := m.Positions[exit.Room]; ok {
+				continue
+			}
+
+			next, ok := m.place(exit.Room, MapPosition{X: pos.X + mapOffsets[dir].X, Y: pos.Y + mapOffsets[dir].Y})
+			if !ok {
+				continue
+			}
+
+			m.Rooms[next] = exit.Room
+			m.Positions[exit.Room] = next
+			queue = append(queue, exit.Room)
+		}
+	}
+
+	return m
+}
+
+// place works out where a room goes, using its coordinates when both it and the origin have them and
+// otherwise the position it was reached at. Rooms on another level, outside the radius or on a spot
+// already taken aren't mapped.
+func (m *RoomMap) place(room *Room, reached MapPosition) (MapPosition, bool) {
+	pos := reached
+	if m.Origin.Corrdinates != nil && room.Corrdinates != nil {
+		if room.Corrdinates.Z != m.Origin.Corrdinates.Z {
+			return pos, false
+		}
+		pos = MapPosition{X: room.Corrdinates.X - m.Origin.Corrdinates.X, Y: room.Corrdinates.Y - m.Origin.Corrdinates.Y}
+	}
+
+	if pos.X < -m.Radius || pos.X > m.Radius || pos.Y < -m.Radius || pos.Y > m.Radius {
+		return pos, false
+	}
+	if _, taken := m.Rooms[pos]; taken {
+		return pos, false
+	}
+
+	return pos, true
+}
+
+// Lines draws the map for the character, one line per row with every line the same width. Rows and
+// columns without any rooms are left off.
+func (m *RoomMap) Lines(char *Character) []string {
+	size := m.Radius*4 + 1
+	grid := make([][]mapCell, size)
+	for i := range grid {
+		grid[i] = make([]mapCell, size)
+	}
+
+	minRow, maxRow, minCol, maxCol := size, -1, size, -1
+	for pos, room := range m.Rooms {
+		row, col := (m.Radius-pos.Y)*2, (pos.X+m.Radius)*2
+		grid[row][col] = mapRoomCell(char, room)
+		minRow, maxRow = min(minRow, row), max(maxRow, row)
+		minCol, maxCol = min(minCol, col), max(maxCol, col)
+
+		// Paths are shared by the rooms on both sides, with a door on either side taking priority
+		for _, dir := range mapDirections {
+			exit, ok := room.GetVisibleExit(char, dir)
+			if !ok {
+				continue
+			}
+			off := mapOffsets[dir]
+			r, c := row-off.Y, col+off.X
+			if r < 0 || r >= size || c < 0 || c >= size {
+				continue
+			}
+			if grid[r][c].glyph != 0 && exit.Door == nil {
+				continue
+			}
+			grid[r][c] = mapPathCell(dir, exit)
+		}
+	}
+
+	lines := make([]string, 0, maxRow-minRow+1)
+	for row := minRow; row <= maxRow; row++ {
+		var sb strings.Builder
+		for col := minCol; col <= maxCol; col++ {
+			cell := grid[row][col]
+			switch {
+			case cell.glyph == 0:
+				sb.WriteByte(' ')
+			case cell.color == "":
+				sb.WriteRune(cell.glyph)
+			default:
+				sb.WriteString(cfmt.Sprintf("{{%c}}::%s", cell.glyph, cell.color))
+			}
+		}
+		lines = append(lines, sb.String())
+	}
+
+	return lines
+}
+
+// Width is the number of columns in the lines of the map.
+func (m *RoomMap) Width() int {
+	minX, maxX := 0, 0
+	for pos := range m.Rooms {
+		minX, maxX = min(minX, pos.X), max(maxX, pos.X)
+	}
+
+	return (maxX-minX)*2 + 1
+}
+
+func mapRoomCell(char *Character, room *Room) mapCell {
+	switch {
+	case room == char.Room:
+		return mapCell{mapGlyphPlayer, "yellow|bold"}
+	case len(room.MobInstances) > 0:
+		return mapCell{mapGlyphMob, "red"}
+	}
+
+	_, up := room.GetVisibleExit(char, "up")
+	_, down := room.GetVisibleExit(char, "down")
+	switch {
+	case up && down:
+		return mapCell{mapGlyphUpDown, "cyan"}
+	case up:
+		return mapCell{mapGlyphUp, "cyan"}
+	case down:
+		return mapCell{mapGlyphDown, "cyan"}
+	}
+
+	return mapCell{mapGlyphRoom, "white"}
+}
+
+func mapPathCell(dir string, exit *Exit) mapCell {
+	switch {
+	case exit.Door != nil && exit.Door.IsClosed:
+		return mapCell{mapGlyphDoorClosed, "yellow"}
+	case exit.Door != nil:
+		return mapCell{mapGlyphDoorOpen, "yellow"}
+	case dir == "north" || dir == "south":
+		return mapCell{mapGlyphPathNS, ""}
+	default:
+		return mapCell{mapGlyphPathEW, ""}
+	}
+}
+
+// RenderMap draws the map around the character's room with a legend.
+func RenderMap(char *Character, radius int) string {
+	m := BuildRoomMap(char.Room, radius, char)
+
+	var sb strings.Builder
+	for _, line := range m.Lines(char) {
+		sb.WriteString(line + CRLF)
+	}
+	sb.WriteString(CRLF)
+	sb.WriteString(cfmt.Sprintf("{{@}}::yellow|bold you  {{#}}::white room  {{M}}::red mobs  {{^}}::cyan up  {{v}}::cyan down  {{X}}::cyan up and down  {{+}}::yellow closed door  {{/}}::yellow open door" + CRLF))
+
+	return sb.String()
+}
+
+// RenderBesideMap draws the map around the character's room to the left of the text, wrapping the text
+// into the space left over.
+func RenderBesideMap(char *Character, text string, width int) string {
+	m := BuildRoomMap(char.Room, AutoMapRadius, char)
+	mapLines := m.Lines(char)
+	mapWidth := m.Width()
+	textLines := strings.Split(wordwrap.String(text, max(width-mapWidth-3, 20)), "\n")
+
+	var sb strings.Builder
+	for i := range max(len(mapLines), len(textLines)) {
+		left := strings.Repeat(" ", mapWidth) + "   "
+		if i < len(mapLines) {
+			left = mapLines[i] + " | "
+		}
+		right := ""
+		if i < len(textLines) {
+			right = textLines[i]
+		}
+		sb.WriteString(strings.TrimRight(left+right, " ") + CRLF)
+	}
+
+	return sb.String()
+}
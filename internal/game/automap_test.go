@@ -0,0 +1,80 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// linkTestRooms adds an exit from one room to another and back.
+func linkTestRooms(from *Room, dir string, to *Room) {
+	if from.Exits == nil {
+		from.Exits = make(map[string]*Exit)
+	}
+	if to.Exits == nil {
+		to.Exits = make(map[string]*Exit)
+	}
+	from.Exits[dir] = &Exit{Room: to, RoomID: to.ID, Direction: dir}
+	to.Exits[ReverseDirection(dir)] = &Exit{Room: from, RoomID: from.ID, Direction: ReverseDirection(dir)}
+}
+
+func TestBuildRoomMapFromExits(t *testing.T) {
+	hall := &Room{ID: "hall"}
+	study := &Room{ID: "study"}
+	kitchen := &Room{ID: "kitchen"}
+	cellar := &Room{ID: "cellar"}
+	linkTestRooms(hall, "north", study)
+	linkTestRooms(hall, "east", kitchen)
+	linkTestRooms(kitchen, "down", cellar)
+
+	m := BuildRoomMap(hall, 2, nil)
+	assert.Equal(t, MapPosition{X: 0, Y: 1}, m.Positions[study])
+	assert.Equal(t, MapPosition{X: 1, Y: 0}, m.Positions[kitchen])
+	assert.NotContains(t, m.Positions, cellar, "rooms on other levels aren't mapped")
+
+	hall.Exits["east"].Door = &Door{IsClosed: true}
+	kitchen.MobInstances = map[string]*MobInstance{"1": {}}
+	char := &Character{Room: hall}
+	lines := m.Lines(char)
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "#  ", stripANSI(lines[0]))
+		assert.Equal(t, "|  ", stripANSI(lines[1]))
+		assert.Equal(t, "@+M", stripANSI(lines[2]))
+	}
+}
+
+func TestBuildRoomMapFromCoordinates(t *testing.T) {
+	hall := &Room{ID: "hall", Corrdinates: &Corrdinates{X: 5, Y: 5}}
+	far := &Room{ID: "far", Corrdinates: &Corrdinates{X: 7, Y: 5}}
+	outside := &Room{ID: "outside", Corrdinates: &Corrdinates{X: 9, Y: 5}}
+	linkTestRooms(hall, "east", far)
+	linkTestRooms(far, "east", outside)
+
+	m := BuildRoomMap(hall, 3, nil)
+	assert.Equal(t, MapPosition{X: 2, Y: 0}, m.Positions[far], "coordinates place rooms over the exits")
+	assert.NotContains(t, m.Positions, outside, "rooms past the radius aren't mapped")
+}
+
+func TestRenderBesideMap(t *testing.T) {
+	hall := &Room{ID: "hall"}
+	linkTestRooms(hall, "west", &Room{ID: "study"})
+
+	out := stripANSI(RenderBesideMap(&Character{Room: hall}, "A long hall.", 80))
+	assert.Equal(t, "#-@ | A long hall.", strings.TrimRight(out, CRLF))
+}
+
+func stripANSI(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+
+	return sb.String()
+}
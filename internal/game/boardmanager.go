@@ -0,0 +1,89 @@
+package game
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	BoardMgr = NewBoardManager()
+)
+
+type BoardManager struct {
+	sync.RWMutex
+
+	boards map[string]*Board
+}
+
+func NewBoardManager() *BoardManager {
+	return &BoardManager{
+		boards: make(map[string]*Board),
+	}
+}
+
+func (mgr *BoardManager) boardFilePath(id string) string {
+	return filepath.Join(viper.GetString("data.boards_path"), strings.ToLower(id)+".yml")
+}
+
+// GetBoard returns the board with the ID, loading it from disk the first time it is used.
+func (mgr *BoardManager) GetBoard(id string) *Board {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	id = strings.ToLower(id)
+	if b, ok := mgr.boards[id]; ok {
+		return b
+	}
+
+	slog.Debug("Loading board",
+		slog.String("board_id", id))
+
+	b := NewBoard(id)
+	if err := LoadYAML(mgr.boardFilePath(id), b); err != nil && !os.IsNotExist(err) {
+		slog.Error("failed to load board",
+			slog.String("board_id", id),
+			slog.Any("error", err))
+	}
+	mgr.boards[id] = b
+
+	return b
+}
+
+// GetRoomBoard returns the board for the first board item in the room.
+func (mgr *BoardManager) GetRoomBoard(room *Room) (*Board, *ItemBlueprint) {
+	for _, item := range room.Inventory.Items {
+		bp := EntityMgr.GetItemBlueprintByInstance(item)
+		if bp != nil && bp.Type == ItemTypeBoard {
+			return mgr.GetBoard(bp.ID), bp
+		}
+	}
+
+	return nil, nil
+}
+
+func (mgr *BoardManager) SaveBoard(b *Board) error {
+	b.RLock()
+	defer b.RUnlock()
+
+	dataFilePath := viper.GetString("data.boards_path")
+	if err := os.MkdirAll(dataFilePath, 0755); err != nil {
+		return err
+	}
+
+	slog.Info("Saving board",
+		slog.String("board_id", b.ID))
+
+	if err := SaveYAML(mgr.boardFilePath(b.ID), b); err != nil {
+		slog.Error("failed to save board",
+			slog.String("board_id", b.ID),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// Board is a bulletin board shared by every instance of a board item blueprint.
+	Board struct {
+		sync.RWMutex `yaml:"-"`
+
+		ID       string          `yaml:"id"`
+		Messages []*BoardMessage `yaml:"messages"`
+	}
+
+	BoardMessage struct {
+		Author   string    `yaml:"author"`
+		Subject  string    `yaml:"subject"`
+		Body     string    `yaml:"body"`
+		PostedAt time.Time `yaml:"posted_at"`
+	}
+)
+
+func NewBoard(id string) *Board {
+	return &Board{
+		ID: id,
+	}
+}
+
+// Post adds a message to the board, dropping the oldest messages when the board is over maxMessages.
+func (b *Board) Post(msg *BoardMessage, maxMessages int) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.Messages = append(b.Messages, msg)
+	if maxMessages > 0 && len(b.Messages) > maxMessages {
+		b.Messages = b.Messages[len(b.Messages)-maxMessages:]
+	}
+}
+
+// GetMessage returns the message at the index, or nil if there isn't one.
+func (b *Board) GetMessage(index int) *BoardMessage {
+	b.RLock()
+	defer b.RUnlock()
+
+	if index < 0 || index >= len(b.Messages) {
+		return nil
+	}
+
+	return b.Messages[index]
+}
+
+// RemoveMessage removes the message at the index from the board.
+func (b *Board) RemoveMessage(index int) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if index < 0 || index >= len(b.Messages) {
+		return fmt.Errorf("invalid message number %d", index+1)
+	}
+
+	b.Messages = slices.Delete(b.Messages, index, index+1)
+
+	return nil
+}
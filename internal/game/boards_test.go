@@ -0,0 +1,22 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoard(t *testing.T) {
+	b := NewBoard("test_board")
+	for _, subject := range []string{"one", "two", "three"} {
+		b.Post(&BoardMessage{Author: "Alice", Subject: subject}, 2)
+	}
+
+	assert.Len(t, b.Messages, 2, "the oldest messages are dropped")
+	assert.Equal(t, "two", b.GetMessage(0).Subject)
+	assert.Nil(t, b.GetMessage(2))
+
+	assert.Error(t, b.RemoveMessage(5))
+	assert.NoError(t, b.RemoveMessage(0))
+	assert.Equal(t, "three", b.GetMessage(0).Subject)
+}
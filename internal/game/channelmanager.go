@@ -0,0 +1,158 @@
+package game
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	ChannelMgr = NewChannelManager()
+)
+
+type ChannelManager struct {
+	sync.RWMutex
+
+	channels map[string]*Channel
+	aliases  map[string]*Channel
+}
+
+func NewChannelManager() *ChannelManager {
+	return &ChannelManager{
+		channels: make(map[string]*Channel),
+		aliases:  make(map[string]*Channel),
+	}
+}
+
+func (mgr *ChannelManager) AddChannel(ch *Channel) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	slog.Debug("Adding channel",
+		slog.String("channel_id", ch.ID),
+		slog.String("scope", ch.Scope))
+
+	mgr.channels[strings.ToLower(ch.ID)] = ch
+	for _, alias := range ch.Aliases {
+		mgr.aliases[strings.ToLower(alias)] = ch
+	}
+}
+
+// GetChannel returns the channel by ID or alias.
+func (mgr *ChannelManager) GetChannel(name string) *Channel {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	name = strings.ToLower(name)
+	if ch, ok := mgr.channels[name]; ok {
+		return ch
+	}
+
+	return mgr.aliases[name]
+}
+
+// GetChannels returns all channels sorted by ID.
+func (mgr *ChannelManager) GetChannels() []*Channel {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	channels := make([]*Channel, 0, len(mgr.channels))
+	for _, ch := range mgr.channels {
+		channels = append(channels, ch)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ID < channels[j].ID
+	})
+
+	return channels
+}
+
+// Send delivers the message to every online character listening on the channel and records it in the history.
+func (mgr *ChannelManager) Send(ch *Channel, sender *Character, message string) {
+	msg := &ChannelMessage{
+		Time:    time.Now(),
+		Channel: ch.ID,
+		Sender:  sender.Name,
+		Message: message,
+	}
+	if sender.Room != nil {
+		msg.AreaID = sender.Room.AreaID
+	}
+	if t := TeamMgr.GetTeam(sender); t != nil {
+		msg.TeamID = t.ID
+	}
+	ch.AddHistory(msg)
+
+	slog.Debug("Channel message",
+		slog.String("channel_id", ch.ID),
+		slog.String("character_name", sender.Name))
+
+	var recipients []*Character
+	formatted := ch.Format(msg) + CRLF
+	for _, c := range CharacterMgr.GetOnlineCharacters() {
+		if c.Conn == nil || !ch.CanReceive(sender, c) {
+			continue
+		}
+
+		if c.SendFrom(sender, formatted) {
+			recipients = append(recipients, c)
+		}
+	}
+
+	MessageLogMgr.Record(&MessageLogEntry{
+		Time:     msg.Time,
+		Type:     MessageTypeChannel,
+		Sender:   sender.Name,
+		SenderID: sender.ID,
+		Channel:  ch.Name,
+		RoomID:   auditRoomID(sender.Room),
+		Message:  message,
+	}, recipients)
+}
+
+func (mgr *ChannelManager) LoadChannels() {
+	slog.Info("Loading channels")
+
+	var channels []*Channel
+	if err := viper.UnmarshalKey("channels", &channels); err != nil {
+		slog.Error("failed to unmarshal channels",
+			slog.Any("error", err))
+		return
+	}
+
+	for _, ch := range channels {
+		if ch.ID == "" {
+			slog.Warn("Skipping channel without an id")
+			continue
+		}
+
+		switch ch.Scope {
+		case "":
+			ch.Scope = ChannelScopeGlobal
+		case ChannelScopeGlobal, ChannelScopeArea, ChannelScopeTeam:
+		case ChannelScopeRole:
+			if ch.Permission == "" {
+				slog.Warn("Role channel has no permission set and can't be used",
+					slog.String("channel_id", ch.ID))
+			}
+		default:
+			slog.Warn("Unknown channel scope",
+				slog.String("channel_id", ch.ID),
+				slog.String("scope", ch.Scope))
+			continue
+		}
+
+		if ch.Name == "" {
+			ch.Name = ch.ID
+		}
+
+		mgr.AddChannel(ch)
+	}
+
+	slog.Info("Loaded channels",
+		slog.Int("count", len(mgr.channels)))
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ChannelScopeGlobal = "global"
+	ChannelScopeArea   = "area"
+	ChannelScopeRole   = "role"
+	ChannelScopeTeam   = "team"
+
+	ChannelDefaultHistorySize = 50
+	ChannelDefaultColor       = "white"
+)
+
+type (
+	Channel struct {
+		sync.RWMutex `yaml:"-" mapstructure:"-"`
+
+		ID          string   `yaml:"id" mapstructure:"id"`
+		Name        string   `yaml:"name" mapstructure:"name"`
+		Description string   `yaml:"description" mapstructure:"description"`
+		Aliases     []string `yaml:"aliases" mapstructure:"aliases"`
+		Scope       string   `yaml:"scope" mapstructure:"scope"`
+		Permission  string   `yaml:"permission" mapstructure:"permission"`
+		Color       string   `yaml:"color" mapstructure:"color"`
+		Default     bool     `yaml:"default" mapstructure:"default"`
+		HistorySize int      `yaml:"history_size" mapstructure:"history_size"`
+
+		history []*ChannelMessage
+	}
+
+	ChannelMessage struct {
+		Time    time.Time
+		Channel string
+		Sender  string
+		AreaID  string
+		TeamID  string
+		Message string
+	}
+)
+
+// CanAccess reports whether the character is allowed to join, send to and receive from the channel.
+func (ch *Channel) CanAccess(char *Character) bool {
+	if ch.Permission == "" {
+		return ch.Scope != ChannelScopeRole
+	}
+
+	return char.HasPermission(ch.Permission)
+}
+
+// IsMember reports whether the character is listening to the channel, falling back to the channel's
+// default when they have never joined or left it.
+func (ch *Channel) IsMember(char *Character) bool {
+	if !ch.CanAccess(char) {
+		return false
+	}
+
+	char.RLock()
+	defer char.RUnlock()
+
+	if joined, ok := char.Channels[ch.ID]; ok {
+		return joined
+	}
+
+	return ch.Default
+}
+
+// CanReceive reports whether a message sent by sender should be delivered to char.
+func (ch *Channel) CanReceive(sender, char *Character) bool {
+	if !ch.IsMember(char) {
+		return false
+	}
+
+	switch ch.Scope {
+	case ChannelScopeArea:
+		if sender.Room == nil || char.Room == nil {
+			return false
+		}
+
+		return strings.EqualFold(sender.Room.AreaID, char.Room.AreaID)
+	case ChannelScopeTeam:
+		return TeamMgr.SameTeam(sender, char)
+	}
+
+	return true
+}
+
+// AddHistory records a message in the channel history, dropping the oldest once the buffer is full.
+func (ch *Channel) AddHistory(msg *ChannelMessage) {
+	ch.Lock()
+	defer ch.Unlock()
+
+	size := ch.HistorySize
+	if size <= 0 {
+		size = ChannelDefaultHistorySize
+	}
+
+	ch.history = append(ch.history, msg)
+	if len(ch.history) > size {
+		ch.history = ch.history[len(ch.history)-size:]
+	}
+}
+
+// GetHistory returns up to count of the most recent messages the character could have received, oldest first.
+func (ch *Channel) GetHistory(char *Character, count int) []*ChannelMessage {
+	ch.RLock()
+	defer ch.RUnlock()
+
+	var messages []*ChannelMessage
+	for i := len(ch.history) - 1; i >= 0 && len(messages) < count; i-- {
+		msg := ch.history[i]
+		if ch.Scope == ChannelScopeArea && (char.Room == nil || !strings.EqualFold(msg.AreaID, char.Room.AreaID)) {
+			continue
+		}
+		if ch.Scope == ChannelScopeTeam {
+			if t := TeamMgr.GetTeam(char); t == nil || t.ID != msg.TeamID {
+				continue
+			}
+		}
+		messages = append([]*ChannelMessage{msg}, messages...)
+	}
+
+	return messages
+}
+
+// Format renders a channel message in the channel's color.
+func (ch *Channel) Format(msg *ChannelMessage) string {
+	color := ch.Color
+	if color == "" {
+		color = ChannelDefaultColor
+	}
+
+	return fmt.Sprintf("{{[%s]}}::%s|bold {{%s: %s}}::%s", ch.Name, color, msg.Sender, msg.Message, color)
+}
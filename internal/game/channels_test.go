@@ -0,0 +1,47 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelHistory(t *testing.T) {
+	ch := &Channel{ID: "ooc", Scope: ChannelScopeGlobal, HistorySize: 3}
+	char := NewCharacter()
+
+	for i := range 5 {
+		ch.AddHistory(&ChannelMessage{Sender: "Bob", Message: fmt.Sprintf("message %d", i)})
+	}
+
+	history := ch.GetHistory(char, 10)
+	assert.Len(t, history, 3, "history is capped at the history size")
+	assert.Equal(t, "message 2", history[0].Message, "oldest retained message comes first")
+	assert.Equal(t, "message 4", history[2].Message)
+
+	history = ch.GetHistory(char, 1)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "message 4", history[0].Message)
+}
+
+func TestChannelAreaScope(t *testing.T) {
+	ch := &Channel{ID: "area", Scope: ChannelScopeArea, Default: true}
+
+	sender := NewCharacter()
+	sender.Room = &Room{AreaID: "limbo"}
+	local := NewCharacter()
+	local.Room = &Room{AreaID: "limbo"}
+	remote := NewCharacter()
+	remote.Room = &Room{AreaID: "seattle"}
+
+	assert.True(t, ch.CanReceive(sender, local))
+	assert.False(t, ch.CanReceive(sender, remote))
+
+	local.SetChannelMembership(ch.ID, false)
+	assert.False(t, ch.CanReceive(sender, local), "characters that left the channel don't receive it")
+
+	ch.AddHistory(&ChannelMessage{AreaID: "limbo", Message: "hello"})
+	assert.Len(t, ch.GetHistory(local, 10), 1)
+	assert.Empty(t, ch.GetHistory(remote, 10), "history is limited to the character's area")
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/spf13/viper"
 )
 
@@ -57,6 +58,16 @@ func (mgr *CharacterManager) SetCharacterOffline(c *Character) {
 	delete(mgr.onlineCharacters, strings.ToLower(c.Name))
 }
 
+// IsOnline reports whether the named character is currently in the game.
+func (mgr *CharacterManager) IsOnline(name string) bool {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	_, ok := mgr.onlineCharacters[strings.ToLower(name)]
+
+	return ok
+}
+
 func (mgr *CharacterManager) AddCharacter(c *Character) {
 	slog.Debug("Adding character",
 		slog.String("character_id", c.ID))
@@ -151,6 +162,11 @@ func (mgr *CharacterManager) LoadDataFiles() {
 				slog.String("file", file.Name()))
 		}
 
+		// Characters created before IDs were assigned need one to be tracked in rooms
+		if c.ID == "" {
+			c.ID = uuid.New().String()
+		}
+
 		mgr.AddCharacter(&c)
 
 		slog.Debug("Loaded character",
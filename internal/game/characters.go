@@ -3,12 +3,14 @@ package game
 import (
 	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gliderlabs/ssh"
+	"github.com/google/uuid"
 	"github.com/i582/cfmt/cmd/cfmt"
 	"github.com/spf13/viper"
 	ee "github.com/vansante/go-event-emitter"
@@ -26,6 +28,13 @@ type (
 		Available int `yaml:"available"` // Karma available to spend
 		Total     int `yaml:"total"`     // Total karma earned
 	}
+	// OfflineTell is a tell sent while the character was offline, delivered at their next login.
+	OfflineTell struct {
+		From     string    `yaml:"from"`
+		Message  string    `yaml:"message"`
+		Language string    `yaml:"language,omitempty"` // Language the sender was speaking
+		SentAt   time.Time `yaml:"sent_at"`
+	}
 	Character struct {
 		sync.RWMutex `yaml:"-"`
 		Listeners    []ee.Listener `yaml:"-"`
@@ -36,19 +45,33 @@ type (
 
 		// GameEntity `yaml:",inline"`
 
-		Conn           ssh.Session `yaml:"-"`
-		RoomID         string      `yaml:"room_id"`
-		Room           *Room       `yaml:"-"`
-		AccountID      string      `yaml:"account_id"`
-		Account        *Account    `yaml:"account"`
-		PregenID       string      `yaml:"pregen_id,omitempty"`
-		Role           string      `yaml:"role"`
-		Prompt         string      `yaml:"prompt,omitempty"`
-		Karma          Karma       `yaml:"karma"`
-		CreatedAt      time.Time   `yaml:"created_at"`
-		UpdatedAt      *time.Time  `yaml:"updated_at,omitempty"`
-		DeletedAt      *time.Time  `yaml:"deleted_at,omitempty"`
-		CommandHistory []string    `yaml:"-"`
+		Conn           ssh.Session     `yaml:"-"`
+		RoomID         string          `yaml:"room_id"`
+		VoidRoomID     string          `yaml:"void_room_id,omitempty"`
+		Room           *Room           `yaml:"-"`
+		AccountID      string          `yaml:"account_id"`
+		Account        *Account        `yaml:"-"`
+		PregenID       string          `yaml:"pregen_id,omitempty"`
+		Role           string          `yaml:"role"`
+		Permissions    []string        `yaml:"permissions,omitempty"`
+		Revoked        []string        `yaml:"revoked_permissions,omitempty"`
+		Channels       map[string]bool `yaml:"channels,omitempty"`
+		Prompt         string          `yaml:"prompt,omitempty"`
+		Language       string          `yaml:"language,omitempty"`
+		InvisLevel     int             `yaml:"invis_level,omitempty"`
+		AutoMap        bool            `yaml:"auto_map,omitempty"` // Show the map beside room descriptions
+		Karma          Karma           `yaml:"karma"`
+		CreatedAt      time.Time       `yaml:"created_at"`
+		UpdatedAt      *time.Time      `yaml:"updated_at,omitempty"`
+		DeletedAt      *time.Time      `yaml:"deleted_at,omitempty"`
+		CommandHistory []string        `yaml:"-"`
+		OfflineTells   []*OfflineTell  `yaml:"offline_tells,omitempty"`
+		ReplyTo        string          `yaml:"-"`
+		AFK            bool            `yaml:"-"`
+		AFKMessage     string          `yaml:"-"`
+
+		afkTells   int
+		foundExits map[string]bool // Hidden exits found this session, keyed by room and direction
 
 		// Inventory     Inventory                `yaml:"inventory"`
 		// Equipment     map[string]*ItemInstance `yaml:"equipment"`
@@ -60,6 +83,9 @@ type (
 
 func NewCharacter() *Character {
 	return &Character{
+		GameEntityInformation: GameEntityInformation{
+			ID: uuid.New().String(),
+		},
 		GameEntityDynamic: NewGameEntityDynamic(),
 		// GameEntity: NewGameEntity(),
 		Role:      CharacterRolePlayer,
@@ -77,6 +103,41 @@ func (c *Character) Send(msg string) {
 	WriteString(c.Conn, msg)
 }
 
+// SendFrom sends a message from another character, dropping it if the character is ignoring the sender.
+// It reports whether the message was delivered.
+func (c *Character) SendFrom(sender *Character, msg string) bool {
+	if c.IsIgnoring(sender) {
+		return false
+	}
+
+	c.Send(msg)
+
+	return true
+}
+
+// IsIgnoring reports whether messages from sender should be hidden from the character. Characters with
+// the bypass permission, such as admins, can't be ignored.
+func (c *Character) IsIgnoring(sender *Character) bool {
+	if sender == nil || sender == c {
+		return false
+	}
+
+	// Characters that haven't logged in since the server started don't have their account set yet
+	account := c.Account
+	if account == nil {
+		account = AccountMgr.GetByCharacterName(c.Name)
+	}
+	if account == nil {
+		return false
+	}
+
+	if sender.HasPermission(PermissionBypassIgnore) {
+		return false
+	}
+
+	return account.IsIgnoring(sender)
+}
+
 func (c *Character) GetName() string {
 	return c.Name
 }
@@ -92,30 +153,269 @@ func (c *Character) ReactToMessage(sender *Character, message string) {
 	}
 }
 
+// HasPermission reports whether the character has the permission through an individual grant or their
+// permission group. Revoked permissions take precedence over both.
+func (c *Character) HasPermission(permission string) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	if MatchAnyPermission(c.Revoked, permission) {
+		return false
+	}
+
+	if MatchAnyPermission(c.Permissions, permission) {
+		return true
+	}
+
+	return PermissionMgr.GroupHasPermission(c.Role, permission)
+}
+
+// GrantPermission grants the character an individual permission, clearing any revocation of it.
+func (c *Character) GrantPermission(permission string) {
+	c.Lock()
+	defer c.Unlock()
+
+	permission = strings.ToLower(permission)
+	c.Revoked = slices.DeleteFunc(c.Revoked, func(p string) bool { return p == permission })
+	if !slices.Contains(c.Permissions, permission) {
+		c.Permissions = append(c.Permissions, permission)
+	}
+}
+
+// RevokePermission removes an individual grant, or explicitly revokes the permission when the character
+// only has it through their group.
+func (c *Character) RevokePermission(permission string) {
+	c.Lock()
+	defer c.Unlock()
+
+	permission = strings.ToLower(permission)
+	if slices.Contains(c.Permissions, permission) {
+		c.Permissions = slices.DeleteFunc(c.Permissions, func(p string) bool { return p == permission })
+		return
+	}
+
+	if !slices.Contains(c.Revoked, permission) {
+		c.Revoked = append(c.Revoked, permission)
+	}
+}
+
+// GetPermissionLevel returns the level of the character's permission group.
+func (c *Character) GetPermissionLevel() int {
+	return PermissionMgr.GetGroupLevel(c.Role)
+}
+
+// ApplyStunDamage fills boxes on the character's Stun Condition Monitor, with damage past the end of it
+// spilling over onto the Physical Condition Monitor.
+func (c *Character) ApplyStunDamage(damage int) {
+	c.Lock()
+	overflow := max(c.StunDamage+damage-c.GetStunConditionMax(), 0)
+	c.StunDamage = min(c.StunDamage+damage, c.GetStunConditionMax())
+	c.Unlock()
+
+	if overflow > 0 {
+		c.ApplyPhysicalDamage(overflow)
+	}
+}
+
+// ApplyPhysicalDamage fills boxes on the character's Physical Condition Monitor, with damage past the end
+// of it going to overflow, up to the most overflow the character can take.
+func (c *Character) ApplyPhysicalDamage(damage int) {
+	c.Lock()
+	defer c.Unlock()
+
+	overflow := max(c.PhysicalDamage+damage-c.GetPhysicalConditionMax(), 0)
+	c.OverflowDamage = min(c.OverflowDamage+overflow, c.GetOverflowMax())
+	c.PhysicalDamage = min(c.PhysicalDamage+damage, c.GetPhysicalConditionMax())
+}
+
+// GetComlink returns the first comlink the character has equipped or is carrying.
+func (c *Character) GetComlink() *ItemInstance {
+	for _, item := range c.Equipment.Slots {
+		if bp := EntityMgr.GetItemBlueprintByInstance(item); bp != nil && bp.Type == ItemTypeComlink {
+			return item
+		}
+	}
+
+	for _, item := range c.Inventory.Items {
+		if bp := EntityMgr.GetItemBlueprintByInstance(item); bp != nil && bp.Type == ItemTypeComlink {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// CanSee reports whether the character can see the target, which they can't when the target is invisible
+// at a level above the character's permission level.
+func (c *Character) CanSee(target *Character) bool {
+	if target == nil || target == c || target.InvisLevel <= 0 {
+		return true
+	}
+
+	return c.GetPermissionLevel() >= target.InvisLevel
+}
+
+// SetAFK marks the character as away from keyboard with an optional message for auto-replies.
+func (c *Character) SetAFK(msg string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.AFK = true
+	c.AFKMessage = msg
+	c.afkTells = 0
+}
+
+// ClearAFK marks the character as back, returning the number of tells they received while away.
+func (c *Character) ClearAFK() int {
+	c.Lock()
+	defer c.Unlock()
+
+	tells := c.afkTells
+	c.AFK = false
+	c.AFKMessage = ""
+	c.afkTells = 0
+
+	return tells
+}
+
+// IsAFK reports whether the character is away from keyboard and their away message.
+func (c *Character) IsAFK() (bool, string) {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.AFK, c.AFKMessage
+}
+
+// ReceiveTell records the sender for reply and counts tells received while AFK.
+func (c *Character) ReceiveTell(from string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.ReplyTo = from
+	if c.AFK {
+		c.afkTells++
+	}
+}
+
+// QueueTell stores a tell, spoken in a language, for delivery at the character's next login. It reports
+// whether there was room for it under server.max_offline_tells.
+func (c *Character) QueueTell(from, message, language string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if maxTells := viper.GetInt("server.max_offline_tells"); maxTells > 0 && len(c.OfflineTells) >= maxTells {
+		return false
+	}
+
+	c.OfflineTells = append(c.OfflineTells, &OfflineTell{
+		From:     from,
+		Message:  message,
+		Language: language,
+		SentAt:   time.Now(),
+	})
+
+	return true
+}
+
+// TakeOfflineTells returns and clears the tells queued while the character was offline.
+func (c *Character) TakeOfflineTells() []*OfflineTell {
+	c.Lock()
+	defer c.Unlock()
+
+	tells := c.OfflineTells
+	c.OfflineTells = nil
+
+	return tells
+}
+
+// Render formats the tell for the character it was sent to, garbled and tagged by their rating in the
+// language it was spoken in, the same as a tell delivered while they're online.
+func (t *OfflineTell) Render(listener *Character) string {
+	language := t.Language
+	if language == "" {
+		language = LanguageDefault
+	}
+
+	return cfmt.Sprintf("{{%s}}::white {{%s told you%s: \"%s\"}}::cyan"+CRLF, t.SentAt.Format("2006-01-02 15:04"),
+		t.From, languageTag(listener, language), GarbleText(t.Message, listener.GetLanguageRating(language)))
+}
+
+// SetChannelMembership records whether the character has joined or left a channel.
+func (c *Character) SetChannelMembership(channelID string, joined bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.Channels == nil {
+		c.Channels = make(map[string]bool)
+	}
+	c.Channels[channelID] = joined
+}
+
 func (c *Character) SetRoom(room *Room) {
 	c.Room = room
-	c.RoomID = room.ReferenceID
+	c.RoomID = room.QualifiedID()
 }
 
 func (c *Character) MoveToRoom(nextRoom *Room) {
 	slog.Debug("Moving character to room",
 		slog.String("character_id", c.ID),
-		slog.String("room_id", nextRoom.ID))
+		slog.String("room_id", nextRoom.QualifiedID()))
 
-	if c.Room != nil && c.Room.ID != nextRoom.ID {
+	if c.Room != nil && c.Room != nextRoom {
 		// EventMgr.Publish(EventRoomCharacterLeave, &RoomCharacterLeave{Character: c, Room: c.Room, NextRoom: nextRoom})
-		c.Room.Broadcast(cfmt.Sprintf("\n{{%s leaves the room.}}::green"+CRLF, c.Name), []string{c.ID})
+		c.Room.BroadcastAbout(c, cfmt.Sprintf("\n{{%s leaves the room.}}::green"+CRLF, c.Name), []string{c.ID})
 		c.Room.RemoveCharacter(c)
 	}
 
 	c.SetRoom(nextRoom)
 	nextRoom.AddCharacter(c)
-	c.Room.Broadcast(cfmt.Sprintf("\n{{%s enters the room.}::green}"+CRLF, c.Name), []string{c.ID})
+	c.Room.BroadcastAbout(c, cfmt.Sprintf("\n{{%s enters the room.}::green}"+CRLF, c.Name), []string{c.ID})
 
 	// EventMgr.Publish(EventRoomCharacterEnter, &RoomCharacterEnter{Character: c, Room: c.Room, PrevRoom: prevRoom})
 	// EventMgr.Publish(EventPlayerEnterRoom, &PlayerEnterRoom{Character: c, Room: c.Room})
 }
 
+// HasKeyFor reports whether the character is carrying a key to the door on one of the room's exits.
+func (c *Character) HasKeyFor(room *Room, door *Door) bool {
+	for _, item := range c.Inventory.Items {
+		bp := EntityMgr.GetItemBlueprintByInstance(item)
+		if bp != nil && bp.Type == ItemTypeKey && door.AcceptsKey(room.AreaID, bp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SendToVoid moves an idle character to the holding room, remembering the room they were in.
+func (c *Character) SendToVoid(void *Room) bool {
+	if void == nil || c.Room == nil || c.Room == void || c.VoidRoomID != "" {
+		return false
+	}
+
+	c.VoidRoomID = c.RoomID
+	c.MoveToRoom(void)
+
+	return true
+}
+
+// ReturnFromVoid moves a character that idled into the holding room back to the room they were in.
+func (c *Character) ReturnFromVoid() bool {
+	if c.VoidRoomID == "" {
+		return false
+	}
+
+	room := EntityMgr.GetRoom(c.VoidRoomID)
+	c.VoidRoomID = ""
+	if room == nil {
+		return false
+	}
+
+	c.MoveToRoom(room)
+
+	return true
+}
+
 func (c *Character) GetArmorValue() int {
 	var totalValue int
 
@@ -216,6 +516,9 @@ func RenderCharacterTable(char *Character) string {
 						cfmt.Sprintf("%-17s %d", "Composure:", char.GetComposure()),
 						cfmt.Sprintf("%-17s %d", "Judge Intentions:", char.GetJudgeIntentions()),
 						cfmt.Sprintf("%-17s %d", "Memory:", char.GetMemory()),
+						cfmt.Sprintf("%-17s %d/%d", "Physical Damage:", char.PhysicalDamage, char.GetPhysicalConditionMax()),
+						cfmt.Sprintf("%-17s %d/%d", "Stun Damage:", char.StunDamage, char.GetStunConditionMax()),
+						cfmt.Sprintf("%-17s %d/%d", "Overflow:", char.OverflowDamage, char.GetOverflowMax()),
 					),
 				),
 			),
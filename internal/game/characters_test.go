@@ -0,0 +1,87 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacterAFK(t *testing.T) {
+	c := newTestCharacter("Alice")
+
+	c.ReceiveTell("Bob")
+	assert.Equal(t, "Bob", c.ReplyTo)
+
+	c.SetAFK("getting soykaf")
+	afk, msg := c.IsAFK()
+	assert.True(t, afk)
+	assert.Equal(t, "getting soykaf", msg)
+
+	c.ReceiveTell("Carol")
+	c.ReceiveTell("Bob")
+	assert.Equal(t, "Bob", c.ReplyTo)
+	assert.Equal(t, 2, c.ClearAFK(), "tells received while away are counted")
+
+	afk, _ = c.IsAFK()
+	assert.False(t, afk)
+}
+
+func TestCharacterOfflineTells(t *testing.T) {
+	c := newTestCharacter("Alice")
+	c.QueueTell("Bob", "call me", LanguageDefault)
+	c.QueueTell("Carol", "got a job for you", "sperethiel")
+
+	tells := c.TakeOfflineTells()
+	assert.Len(t, tells, 2)
+	assert.Equal(t, "Bob", tells[0].From)
+	assert.Empty(t, c.TakeOfflineTells())
+
+	// Offline tells are garbled and tagged by language when they're delivered, like online ones
+	assert.Contains(t, stripANSI(tells[0].Render(c)), `Bob told you: "call me"`)
+	out := stripANSI(tells[1].Render(c))
+	assert.Contains(t, out, "Carol told you in a language you don't understand")
+	assert.NotContains(t, out, "got a job for you")
+
+	c.Skills["sperethiel"] = &Skill{BlueprintID: "sperethiel", Rating: LanguageFluentRating}
+	assert.Contains(t, stripANSI(tells[1].Render(c)), `"got a job for you"`)
+
+	viper.Set("server.max_offline_tells", 1)
+	defer viper.Set("server.max_offline_tells", nil)
+	assert.True(t, c.QueueTell("Bob", "call me", LanguageDefault))
+	assert.False(t, c.QueueTell("Bob", "call me again", LanguageDefault), "full queues refuse new tells")
+	assert.Len(t, c.TakeOfflineTells(), 1)
+}
+
+func TestCharacterVoid(t *testing.T) {
+	street := &Room{ID: "test_street", Characters: make(map[string]*Character)}
+	void := &Room{ID: "test_void", Characters: make(map[string]*Character)}
+	EntityMgr.AddRoom(street)
+	defer EntityMgr.RemoveRoom(street)
+
+	c := newTestCharacter("Alice")
+	c.SetRoom(street)
+	street.AddCharacter(c)
+
+	assert.False(t, c.ReturnFromVoid(), "a character that isn't in the void stays put")
+
+	assert.True(t, c.SendToVoid(void))
+	assert.Equal(t, void, c.Room)
+	assert.Equal(t, "test_street", c.VoidRoomID)
+	assert.NotContains(t, street.Characters, c.ID)
+	assert.False(t, c.SendToVoid(void), "already in the void")
+
+	assert.True(t, c.ReturnFromVoid())
+	assert.Equal(t, street, c.Room)
+	assert.Empty(t, c.VoidRoomID)
+	assert.Contains(t, street.Characters, c.ID)
+
+	// Another area's room with the same ID isn't the room the character is in
+	elsewhere := &Room{ID: "test_street", AreaID: "other", Characters: make(map[string]*Character)}
+	c.MoveToRoom(elsewhere)
+	assert.NotContains(t, street.Characters, c.ID)
+	assert.Contains(t, elsewhere.Characters, c.ID)
+
+	otherVoid := &Room{ID: "test_street", AreaID: "void", Characters: make(map[string]*Character)}
+	assert.True(t, c.SendToVoid(otherVoid))
+}
@@ -41,6 +41,22 @@ func changePasswordState(s ssh.Session, ctx *GameContext) string {
 	return PromptChangePassword(s, ctx.Account)
 }
 
+func changeEmailState(s ssh.Session, ctx *GameContext) string {
+	return PromptChangeEmail(s, ctx.Account)
+}
+
+func passwordResetState(s ssh.Session, ctx *GameContext) string {
+	return PromptPasswordReset(s)
+}
+
+func accountDeleteState(s ssh.Session, ctx *GameContext) string {
+	return PromptAccountDelete(s, ctx.Account)
+}
+
+func manageKeysState(s ssh.Session, ctx *GameContext) string {
+	return PromptManageKeys(s, ctx.Account)
+}
+
 func characterCreateState(s ssh.Session, ctx *GameContext) string {
 	state, char := PromptCharacterCreate(s, ctx.Account)
 	ctx.Character = char
@@ -71,6 +87,10 @@ var stateHandlers = map[string]stateHandler{
 	StateRegistration:    registrationState,
 	StateMainMenu:        mainMenuState,
 	StateChangePassword:  changePasswordState,
+	StateManageKeys:      manageKeysState,
+	StateChangeEmail:     changeEmailState,
+	StatePasswordReset:   passwordResetState,
+	StateAccountDelete:   accountDeleteState,
 	StateCharacterCreate: characterCreateState,
 	StateCharacterDelete: characterDeleteState,
 	StateEnterGame:       enterGameState,
@@ -81,6 +101,15 @@ var stateHandlers = map[string]stateHandler{
 func handleConnection(s ssh.Session) {
 	defer s.Close()
 
+	if _, err := SessionMgr.AddSession(s); err != nil {
+		slog.Warn("Rejected connection",
+			slog.String("remote_address", s.RemoteAddr().String()),
+			slog.Any("error", err))
+		WriteStringF(s, "{{%s}}::red"+CRLF, err.Error())
+		return
+	}
+	defer SessionMgr.RemoveSession(s)
+
 	_, winCh, _ := s.Pty()
 	go func() {
 		for win := range winCh {
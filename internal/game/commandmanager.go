@@ -143,6 +143,17 @@ func (mgr *CommandManager) ParseAndExecute(s ssh.Session, input string, user *Ac
 		return
 	}
 
+	// Unconscious characters can't do much more than check on themselves
+	if char.IsUnconscious() && !command.CanUseWhileUnconscious() {
+		WriteString(s, "{{You can't do that while you're unconscious.}}::red"+CRLF)
+		return
+	}
+
+	// Record every use of a privileged command
+	if command.RequiredPermission != "" && !command.SkipAudit {
+		AuditMgr.LogCommand(char, command, args, room)
+	}
+
 	// Provide command suggestions if applicable
 	if command.SuggestFunc != nil {
 		suggestions := command.SuggestFunc(input, args, char, room)
@@ -156,20 +167,11 @@ func (mgr *CommandManager) ParseAndExecute(s ssh.Session, input string, user *Ac
 }
 
 func (mgr *CommandManager) CanRunCommand(char *Character, cmd *Command) bool {
-	if len(cmd.RequiredRoles) == 0 {
+	if cmd.RequiredPermission == "" {
 		return true
 	}
 
-	requiredRoles := make(map[string]bool)
-	for _, role := range cmd.RequiredRoles {
-		requiredRoles[role] = true
-	}
-
-	if _, ok := requiredRoles[char.Role]; !ok {
-		return false
-	}
-
-	return true
+	return char.HasPermission(cmd.RequiredPermission)
 }
 
 func ParseArguments(input string) (string, []string) {
@@ -182,11 +184,6 @@ func ParseArguments(input string) (string, []string) {
 
 // CanSeeCommand determines if a character can view a specific command
 func CanSeeCommand(char *Character, command *Command) bool {
-	// Admin-only commands should only be shown to admins
-	if command.CommandCategory == CommandCategoryAdministration || len(command.RequiredRoles) > 0 {
-		if char.Role != CharacterRoleAdmin {
-			return false
-		}
-	}
-	return true
+	// Privileged commands should only be shown to characters with the permission to run them
+	return CommandMgr.CanRunCommand(char, command)
 }
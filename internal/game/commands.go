@@ -1,17 +1,19 @@
 package game
 
 import (
+	"log/slog"
+
 	"github.com/gliderlabs/ssh"
 )
 
-// TODO: We need a RP consistent way to communicate directly with other individuals or groups of individuals I.E. for shadowrun it could be via comlinks and some group or party system
-
 const (
 	CommandCategoryAdministration CommandCategory = "Administration"
 	CommandCategoryCommunication  CommandCategory = "Communication"
 	CommandCategoryInformative    CommandCategory = "Informative"
 	CommandCategoryMovement       CommandCategory = "Movement"
 	CommandCategoryInteraction    CommandCategory = "Interaction"
+	CommandCategorySocial         CommandCategory = "Social"
+	CommandCategoryBuilding       CommandCategory = "Building"
 )
 
 type (
@@ -21,14 +23,15 @@ type (
 	SuggestFunc func(line string, args []string, char *Character, room *Room) []string
 
 	Command struct {
-		Name            string
-		Description     string
-		CommandCategory CommandCategory
-		Usage           []string
-		Aliases         []string
-		RequiredRoles   []string
-		Func            CommandFunc
-		SuggestFunc     SuggestFunc // Optional suggestion logic
+		Name               string
+		Description        string
+		CommandCategory    CommandCategory
+		Usage              []string
+		Aliases            []string
+		RequiredPermission string
+		SkipAudit          bool // Privileged commands that are only chat, such as staff channels, aren't audited
+		Func               CommandFunc
+		SuggestFunc        SuggestFunc // Optional suggestion logic
 	}
 )
 
@@ -56,28 +59,84 @@ func RegisterCommands() {
 		Func:            DoUnequip,
 	})
 	CommandMgr.RegisterCommand(Command{
-		Name:            "list",
-		Description:     "List game entities",
-		CommandCategory: CommandCategoryAdministration,
-		Usage:           []string{"list <mobs|m> [tags]", "list <rooms|r> [tags]", "list <items|i> [tags]"},
-		RequiredRoles:   []string{CharacterRoleAdmin},
-		Func:            DoList,
+		Name:               "list",
+		Description:        "List game entities",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"list <areas|a> [flags]", "list <mobs|m> [tags]", "list <rooms|r> [tags]", "list <items|i> [tags]"},
+		RequiredPermission: PermissionWorldList,
+		Func:               DoList,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "goto",
+		Description:        "Teleport to a room or character",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"goto <room_id>", "goto <character_name>"},
+		RequiredPermission: PermissionWorldGoto,
+		Func:               DoGoto,
 	})
 	CommandMgr.RegisterCommand(Command{
-		Name:            "goto",
-		Description:     "Teleport to a room or character",
-		CommandCategory: CommandCategoryAdministration,
-		Usage:           []string{"goto <room_id>", "goto <character_name>"},
-		RequiredRoles:   []string{CharacterRoleAdmin},
-		Func:            DoGoto,
+		Name:               "mobstats",
+		Description:        "Display the stats of a mob",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"mobstats <mob>"},
+		Func:               DoMobStats,
+		RequiredPermission: PermissionWorldMobStats,
 	})
 	CommandMgr.RegisterCommand(Command{
-		Name:            "mobstats",
-		Description:     "Display the stats of a mob",
-		CommandCategory: CommandCategoryAdministration,
-		Usage:           []string{"mobstats <mob>"},
-		Func:            DoMobStats,
-		RequiredRoles:   []string{CharacterRoleAdmin},
+		Name:               "reload",
+		Description:        "Reload an area from its data files",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"reload area <area_id>"},
+		RequiredPermission: PermissionWorldReload,
+		Func:               DoReload,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "worldlint",
+		Description:        "Check the area files for broken exits, spawns and duplicate IDs",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"worldlint [area_id]"},
+		RequiredPermission: PermissionWorldLint,
+		Func:               DoWorldLint,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "redit",
+		Description:        "Edit a room, its exits and spawns",
+		CommandCategory:    CommandCategoryBuilding,
+		Usage:              []string{"redit [room_id]", "redit new <room_id>"},
+		RequiredPermission: PermissionWorldEdit,
+		Func:               DoRedit,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "oedit",
+		Description:        "Edit an item blueprint",
+		CommandCategory:    CommandCategoryBuilding,
+		Usage:              []string{"oedit <item_id>", "oedit new <item_id>"},
+		RequiredPermission: PermissionWorldEdit,
+		Func:               DoOedit,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "medit",
+		Description:        "Edit a mob blueprint",
+		CommandCategory:    CommandCategoryBuilding,
+		Usage:              []string{"medit <mob_id>", "medit new <mob_id>"},
+		RequiredPermission: PermissionWorldEdit,
+		Func:               DoMedit,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "aedit",
+		Description:        "Edit an area",
+		CommandCategory:    CommandCategoryBuilding,
+		Usage:              []string{"aedit [area_id]", "aedit new <area_id>"},
+		RequiredPermission: PermissionWorldEdit,
+		Func:               DoAedit,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "save",
+		Description:        "Write an area back to its data files",
+		CommandCategory:    CommandCategoryBuilding,
+		Usage:              []string{"save area [area_id]"},
+		RequiredPermission: PermissionWorldEdit,
+		Func:               DoSave,
 	})
 	CommandMgr.RegisterCommand(Command{
 		Name:            "prompt",
@@ -146,10 +205,31 @@ func RegisterCommands() {
 		Name:            "who",
 		Description:     "List players currently in the game",
 		CommandCategory: CommandCategoryInformative,
-		Usage:           []string{"who"},
+		Usage:           []string{"who [name|role|afk]", "who metatype:<metatype> area:<area> role:<role>", "who sort:<level|name|idle|area>"},
 		Aliases:         []string{"w"},
 		Func:            DoWho,
 	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "map",
+		Description:     "Show a map of the rooms around you",
+		CommandCategory: CommandCategoryInformative,
+		Usage:           []string{"map [radius]", "map auto"},
+		Func:            DoMap,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "search",
+		Description:     "Search the room for hidden exits",
+		CommandCategory: CommandCategoryInformative,
+		Usage:           []string{"search"},
+		Func:            DoSearch,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "areas",
+		Description:     "List the areas of the world",
+		CommandCategory: CommandCategoryInformative,
+		Usage:           []string{"areas [area_id]"},
+		Func:            DoAreas,
+	})
 	CommandMgr.RegisterCommand(Command{
 		Name:            "look",
 		Description:     "Look around the room",
@@ -199,6 +279,22 @@ func RegisterCommands() {
 		Aliases:         []string{"m", "n", "s", "e", "w", "u", "d", "north", "south", "east", "west", "up", "down"},
 		Func:            DoMove,
 	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "run",
+		Description:     "Move several rooms at once with a speedwalk",
+		CommandCategory: CommandCategoryMovement,
+		Usage:           []string{"run <speedwalk>", "run 3n2e"},
+		Aliases:         []string{"speedwalk"},
+		Func:            DoRun,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "path",
+		Description:     "Find the way to a room or landmark",
+		CommandCategory: CommandCategoryMovement,
+		Usage:           []string{"path <room|landmark>"},
+		Aliases:         []string{"travel"},
+		Func:            DoPath,
+	})
 	CommandMgr.RegisterCommand(Command{
 		Name:            "inventory",
 		Description:     "List your inventory",
@@ -218,16 +314,232 @@ func RegisterCommands() {
 		Name:            "tell",
 		Description:     "Send a private message to a specific character.",
 		CommandCategory: CommandCategoryCommunication,
-		Usage:           []string{"tell <username> <message>"},
+		Usage:           []string{"tell <character> <message>"},
 		Func:            DoTell,
 		SuggestFunc:     SuggestTell,
 	})
 	CommandMgr.RegisterCommand(Command{
-		Name:            "spawn",
-		Description:     "Spawn an item or mob into the room",
-		CommandCategory: CommandCategoryAdministration,
-		Usage:           []string{"spawn item <item>", "spawn mob <mob>"},
-		RequiredRoles:   []string{CharacterRoleAdmin},
-		Func:            DoSpawn,
+		Name:            "speak",
+		Description:     "Show or change the language you are speaking.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"speak", "speak <language>"},
+		Func:            DoSpeak,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "reply",
+		Description:     "Reply to the last character who sent you a tell.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"reply <message>"},
+		Func:            DoReply,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "afk",
+		Description:     "Mark yourself as away from the keyboard, with an optional auto-reply message.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"afk", "afk <message>"},
+		Func:            DoAFK,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "emote",
+		Aliases:         []string{"pose"},
+		Description:     "Describe an action to everyone in the room.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"emote <action>"},
+		Func:            DoEmote,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "socials",
+		Description:     "List the available socials.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"socials"},
+		Func:            DoSocials,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "mail",
+		Description:     "Send and read mail, even to characters who are offline.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"mail [list]", "mail read <number>", "mail send <character> [subject]", "mail delete <number>"},
+		Func:            DoMail,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "board",
+		Description:     "Read and post to a bulletin board in the room.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"board [list]", "board read <number>", "board post <subject>", "board remove <number>"},
+		Func:            DoBoard,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "ignore",
+		Description:     "Ignore messages from a character and the rest of their account.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"ignore", "ignore <character>"},
+		Func:            DoIgnore,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "unignore",
+		Description:     "Stop ignoring a character.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"unignore <character>"},
+		Func:            DoUnignore,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "spawn",
+		Description:        "Spawn an item or mob into the room",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"spawn item <item>", "spawn mob <mob>"},
+		RequiredPermission: PermissionWorldSpawn,
+		Func:               DoSpawn,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "grant",
+		Description:        "Grant a permission to a character",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"grant <character> <permission>"},
+		RequiredPermission: PermissionAdminGrant,
+		Func:               DoGrant,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "revoke",
+		Description:        "Revoke a permission from a character",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"revoke <character> <permission>"},
+		RequiredPermission: PermissionAdminGrant,
+		Func:               DoRevoke,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "permissions",
+		Description:        "List the permission groups or a character's permissions",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"permissions", "permissions <character>"},
+		Aliases:            []string{"perms"},
+		RequiredPermission: PermissionAdminPermissions,
+		Func:               DoPermissions,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "audit",
+		Description:        "Query recent audit log entries",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"audit [limit]", "audit character <name> [limit]", "audit type <command|entity_created|transfer|permission> [limit]"},
+		RequiredPermission: PermissionAdminAudit,
+		Func:               DoAudit,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "chatlog",
+		Description:        "Search the message log for a character's says, tells and channel messages",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"chatlog <character> [since]"},
+		RequiredPermission: PermissionAdminChatlog,
+		Func:               DoChatlog,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "invis",
+		Description:        "Toggle invisibility to characters below your permission level",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"invis"},
+		RequiredPermission: PermissionAdminInvis,
+		Func:               DoInvis,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:               "wizinvis",
+		Description:        "Set the permission level a character needs to see you",
+		CommandCategory:    CommandCategoryAdministration,
+		Usage:              []string{"wizinvis [level]"},
+		RequiredPermission: PermissionAdminInvis,
+		Func:               DoWizinvis,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "replay",
+		Description:     "Show the most recent messages you have received.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"replay [count]"},
+		Func:            DoReplay,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "lasttells",
+		Description:     "Show the most recent tells you have received.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"lasttells [count]"},
+		Func:            DoReplay,
 	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "text",
+		Description:     "Send a text message to another character's comlink.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"text <character> <message>"},
+		Func:            DoText,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "call",
+		Description:     "Speak to another character over your comlink.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"call <character> <message>"},
+		Func:            DoCall,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "team",
+		Description:     "Manage your team. Use the teamchat channel to talk to your team.",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"team", "team invite <character>", "team accept <character>", "team leave", "team kick <character>"},
+		Func:            DoTeam,
+	})
+	CommandMgr.RegisterCommand(Command{
+		Name:            "channels",
+		Description:     "List the channels available to you",
+		CommandCategory: CommandCategoryCommunication,
+		Usage:           []string{"channels"},
+		Func:            DoChannels,
+	})
+
+	RegisterChannelCommands()
+	RegisterSocialCommands()
+}
+
+// RegisterSocialCommands registers every loaded social as a command verb.
+func RegisterSocialCommands() {
+	for _, social := range SocialMgr.GetSocials() {
+		if _, ok := CommandMgr.GetCommands()[social.ID]; ok {
+			slog.Warn("Social conflicts with an existing command",
+				slog.String("social_id", social.ID))
+			continue
+		}
+
+		usage := []string{social.ID}
+		if social.HasTarget() {
+			usage = append(usage, social.ID+" <target>")
+		}
+		if social.HasSelf() {
+			usage = append(usage, social.ID+" self")
+		}
+
+		CommandMgr.RegisterCommand(Command{
+			Name:            social.ID,
+			Description:     social.Description,
+			CommandCategory: CommandCategorySocial,
+			Usage:           usage,
+			Func:            DoSocial,
+			SuggestFunc:     SuggestSocial,
+		})
+	}
+}
+
+// RegisterChannelCommands registers every configured channel as a command verb.
+func RegisterChannelCommands() {
+	for _, ch := range ChannelMgr.GetChannels() {
+		if _, ok := CommandMgr.GetCommands()[ch.ID]; ok {
+			slog.Warn("Channel conflicts with an existing command",
+				slog.String("channel_id", ch.ID))
+			continue
+		}
+
+		CommandMgr.RegisterCommand(Command{
+			Name:               ch.ID,
+			Aliases:            ch.Aliases,
+			Description:        ch.Description,
+			CommandCategory:    CommandCategoryCommunication,
+			Usage:              []string{ch.ID + " <message>", ch.ID + " last [count]", ch.ID + " join", ch.ID + " leave", ch.ID + " who"},
+			RequiredPermission: ch.Permission,
+			SkipAudit:          true,
+			Func:               DoChannel,
+		})
+	}
 }
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/spf13/viper"
+)
+
+const (
+	HealingInterval = 60 // Game minutes between rounds of natural healing
+)
+
+// unconsciousCategories are the kinds of commands a character can still use while unconscious.
+var unconsciousCategories = []CommandCategory{
+	CommandCategoryInformative,
+	CommandCategoryAdministration,
+	CommandCategoryBuilding,
+}
+
+// GetOverflowMax returns the boxes of overflow damage the character can take past a full Physical
+// Condition Monitor, one per point of Body. Filling them kills the character.
+func (c *Character) GetOverflowMax() int {
+	return c.GetBody()
+}
+
+// IsUnconscious reports whether either of the character's condition monitors is full.
+func (c *Character) IsUnconscious() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.StunDamage >= c.GetStunConditionMax() || c.PhysicalDamage >= c.GetPhysicalConditionMax()
+}
+
+// CanUseWhileUnconscious reports whether the command can be used by an unconscious character.
+func (cmd Command) CanUseWhileUnconscious() bool {
+	return slices.Contains(unconsciousCategories, cmd.CommandCategory)
+}
+
+// PulseHealing lets every online character recover from their damage.
+func PulseHealing() {
+	for _, c := range CharacterMgr.GetOnlineCharacters() {
+		for _, msg := range c.Heal() {
+			c.Send(msg)
+		}
+	}
+}
+
+// Heal recovers the character's damage with natural healing, a Body + Willpower test for stun damage and a
+// Body x 2 test for physical damage, healing overflow before the physical boxes under it. It returns the
+// messages telling them what happened.
+func (c *Character) Heal() []string {
+	c.Lock()
+	if c.StunDamage > 0 {
+		c.StunDamage = max(c.StunDamage-rollTest(c.GetBody()+c.GetWillpower()), 0)
+	}
+	if c.PhysicalDamage > 0 || c.OverflowDamage > 0 {
+		healed := rollTest(c.GetBody() * 2)
+		overflow := min(healed, c.OverflowDamage)
+		c.OverflowDamage -= overflow
+		c.PhysicalDamage = max(c.PhysicalDamage-(healed-overflow), 0)
+	}
+	c.Unlock()
+
+	return c.UpdateCondition()
+}
+
+// UpdateCondition applies what the character's damage does to them and returns the messages telling them.
+// Filling either condition monitor knocks them out until they heal below it. Filling the overflow kills
+// them, and a trauma team drags them back to the starting room with one box left on their Physical
+// Condition Monitor. The character is saved so they don't come back where they died.
+func (c *Character) UpdateCondition() []string {
+	c.Lock()
+	if c.OverflowDamage >= c.GetOverflowMax() {
+		c.OverflowDamage = 0
+		c.PhysicalDamage = c.GetPhysicalConditionMax() - 1
+		c.StunDamage = 0
+		c.PositionState = PositionStanding
+		c.Unlock()
+
+		if room := EntityMgr.GetRoom(viper.GetString("server.starting_room")); room != nil && room != c.Room {
+			c.MoveToRoom(room)
+		}
+		c.Save()
+
+		return []string{cfmt.Sprintf("{{Your heart stops. A trauma team hauls you back from the brink, and you wake up somewhere safe, barely alive.}}::red" + CRLF)}
+	}
+
+	unconscious := c.StunDamage >= c.GetStunConditionMax() || c.PhysicalDamage >= c.GetPhysicalConditionMax()
+	var msgs []string
+	switch {
+	case unconscious && c.PositionState != PositionUnconscious:
+		c.PositionState = PositionUnconscious
+		msgs = append(msgs, cfmt.Sprintf("{{You collapse, unconscious.}}::red"+CRLF))
+	case !unconscious && c.PositionState == PositionUnconscious:
+		c.PositionState = PositionStanding
+		msgs = append(msgs, cfmt.Sprintf("{{You come to.}}::green"+CRLF))
+	}
+	c.Unlock()
+
+	return msgs
+}
+
+// conditionColor returns the color for a condition monitor with the damage on it.
+func conditionColor(damage, boxes int) string {
+	switch {
+	case damage == 0:
+		return "green"
+	case damage >= boxes:
+		return "red"
+	default:
+		return "yellow"
+	}
+}
+
+// GetFormattedCondition returns the character's condition monitors for the prompt, with the overflow only
+// when they have some.
+func GetFormattedCondition(char *Character) string {
+	char.RLock()
+	defer char.RUnlock()
+
+	physical, stun := char.GetPhysicalConditionMax(), char.GetStunConditionMax()
+	condition := fmt.Sprintf("{{P:%d/%d}}::%s {{S:%d/%d}}::%s ",
+		char.PhysicalDamage, physical, conditionColor(char.PhysicalDamage, physical),
+		char.StunDamage, stun, conditionColor(char.StunDamage, stun))
+	if char.OverflowDamage > 0 {
+		condition += fmt.Sprintf("{{O:%d/%d}}::red ", char.OverflowDamage, char.GetOverflowMax())
+	}
+
+	return condition
+}
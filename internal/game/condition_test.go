@@ -0,0 +1,84 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverflowIsCappedAtBody(t *testing.T) {
+	char := newTestCharacter("Bob")
+	char.Body = 4
+
+	char.ApplyPhysicalDamage(char.GetPhysicalConditionMax() + 100)
+	assert.Equal(t, char.GetPhysicalConditionMax(), char.PhysicalDamage)
+	assert.Equal(t, 4, char.OverflowDamage)
+}
+
+func TestConditionAndHealing(t *testing.T) {
+	char := newTestCharacter("Bob")
+	char.Body = 4
+	char.Willpower = 2
+
+	char.ApplyStunDamage(char.GetStunConditionMax())
+	assert.True(t, char.IsUnconscious())
+	assert.Len(t, char.UpdateCondition(), 1)
+	assert.Equal(t, PositionUnconscious, char.PositionState)
+	assert.Empty(t, char.UpdateCondition(), "nothing more happens until they heal")
+	assert.False(t, Command{CommandCategory: CommandCategoryMovement}.CanUseWhileUnconscious())
+	assert.True(t, Command{CommandCategory: CommandCategoryInformative}.CanUseWhileUnconscious())
+
+	withTestRolls(t, 2)
+	msgs := char.Heal()
+	assert.Equal(t, char.GetStunConditionMax()-2, char.StunDamage)
+	assert.False(t, char.IsUnconscious())
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, PositionStanding, char.PositionState)
+
+	// Overflow heals before the physical damage under it
+	char.PhysicalDamage = char.GetPhysicalConditionMax()
+	char.OverflowDamage = 1
+	char.Heal()
+	assert.Zero(t, char.OverflowDamage)
+	assert.Equal(t, char.GetPhysicalConditionMax()-1, char.PhysicalDamage)
+}
+
+func TestFullOverflowKills(t *testing.T) {
+	start := &Room{ID: "test_start", Characters: make(map[string]*Character)}
+	street := &Room{ID: "test_street", Characters: make(map[string]*Character)}
+	EntityMgr.AddRoom(start)
+	defer EntityMgr.RemoveRoom(start)
+	viper.Set("server.starting_room", "test_start")
+	defer viper.Set("server.starting_room", nil)
+	dir := t.TempDir()
+	viper.Set("data.characters_path", dir)
+	defer viper.Set("data.characters_path", nil)
+
+	char := newTestCharacter("Bob")
+	char.Body = 3
+	char.SetRoom(street)
+	street.AddCharacter(char)
+
+	char.ApplyPhysicalDamage(char.GetPhysicalConditionMax() + char.GetOverflowMax())
+	assert.Len(t, char.UpdateCondition(), 1)
+	assert.Equal(t, start, char.Room, "a trauma team takes them to the starting room")
+	assert.Zero(t, char.OverflowDamage)
+	assert.Equal(t, char.GetPhysicalConditionMax()-1, char.PhysicalDamage)
+	assert.False(t, char.IsUnconscious())
+	assert.FileExists(t, filepath.Join(dir, "bob.yml"), "they're saved where they woke up")
+}
+
+func TestFormattedCondition(t *testing.T) {
+	char := newTestCharacter("Bob")
+	char.Body = 4
+	char.Willpower = 2
+	char.PhysicalDamage = 3
+
+	assert.Equal(t, "P:3/10 S:0/9 ", stripANSI(cfmt.Sprint(GetFormattedCondition(char))))
+
+	char.OverflowDamage = 1
+	assert.Contains(t, stripANSI(cfmt.Sprint(GetFormattedCondition(char))), "O:1/4")
+}
@@ -17,29 +17,30 @@ type EntityManager struct {
 	sync.RWMutex
 
 	areas           map[string]*Area
-	itemsBlueprints map[string]*ItemBlueprint
+	itemsBlueprints *areaIndex[*ItemBlueprint]
 	itemInstances   map[string]*ItemInstance
 	metatypes       map[string]*Metatype
-	mobBlueprints   map[string]*MobBlueprint
+	mobBlueprints   *areaIndex[*MobBlueprint]
 	mobInstances    map[string]*MobInstance
 	pregens         map[string]*Pregen
 	qualtities      map[string]*QualityBlueprint
-	rooms           map[string]*Room
+	rooms           *areaIndex[*Room]
 	skills          map[string]*SkillBlueprint
 	skillGroups     map[string]*SkillGroup
+	pendingReloads  []areaReload
 }
 
 func NewEntityManager() *EntityManager {
 	return &EntityManager{
 		areas:           make(map[string]*Area),
-		itemsBlueprints: make(map[string]*ItemBlueprint),
+		itemsBlueprints: newAreaIndex[*ItemBlueprint](),
 		itemInstances:   make(map[string]*ItemInstance),
 		metatypes:       make(map[string]*Metatype),
-		mobBlueprints:   make(map[string]*MobBlueprint),
+		mobBlueprints:   newAreaIndex[*MobBlueprint](),
 		mobInstances:    make(map[string]*MobInstance),
 		pregens:         make(map[string]*Pregen),
 		qualtities:      make(map[string]*QualityBlueprint),
-		rooms:           make(map[string]*Room),
+		rooms:           newAreaIndex[*Room](),
 		skills:          make(map[string]*SkillBlueprint),
 		skillGroups:     make(map[string]*SkillGroup),
 	}
@@ -63,9 +64,9 @@ func (mgr *EntityManager) LoadDataFiles() {
 	slog.Info("Loaded data files",
 		slog.Duration("took", took),
 		slog.Int("areas", len(mgr.areas)),
-		slog.Int("items", len(mgr.itemsBlueprints)),
-		slog.Int("mobs", len(mgr.mobBlueprints)),
-		slog.Int("rooms", len(mgr.rooms)),
+		slog.Int("items", mgr.itemsBlueprints.len()),
+		slog.Int("mobs", mgr.mobBlueprints.len()),
+		slog.Int("rooms", mgr.rooms.len()),
 		slog.Int("pregens", len(mgr.pregens)),
 		slog.Int("qualities", len(mgr.qualtities)),
 		slog.Int("skills", len(mgr.skills)),
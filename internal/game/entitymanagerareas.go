@@ -1,11 +1,20 @@
 package game
 
 import (
+	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
+	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,6 +29,13 @@ func (mgr *EntityManager) AddArea(a *Area) {
 	mgr.areas[strings.ToLower(a.ID)] = a
 }
 
+func (mgr *EntityManager) GetAllAreas() map[string]*Area {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return mgr.areas
+}
+
 func (mgr *EntityManager) GetArea(areaID string) *Area {
 	mgr.RLock()
 	defer mgr.RUnlock()
@@ -40,6 +56,102 @@ func (mgr *EntityManager) RemoveArea(a *Area) {
 	delete(mgr.areas, strings.ToLower(a.ID))
 }
 
+// areaData is everything read from an area's directory.
+type areaData struct {
+	Area  *Area
+	Rooms []*Room
+	Items []*ItemBlueprint
+	Mobs  []*MobBlueprint
+
+	Errors []error // Files that couldn't be parsed and were skipped
+}
+
+// readArea reads an area's manifest, rooms, items and mobs without adding them to the manager. Room, item
+// and mob files that can't be parsed are skipped and returned in the data's errors.
+func (mgr *EntityManager) readArea(areaFS fs.FS, dir string) (*areaData, error) {
+	// Load the area manifest
+	manifestBytes, err := fs.ReadFile(areaFS, AreasFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading manifest: %w", err)
+	}
+	var area Area
+	if err := yaml.Unmarshal(manifestBytes, &area); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest data: %w", err)
+	}
+	area.Dir = dir
+
+	data := &areaData{Area: &area}
+
+	// Load rooms
+	loadFilesFromDir(areaFS, "rooms", func(name string, b []byte) {
+		var room Room
+		if err := yaml.Unmarshal(b, &room); err != nil {
+			data.Errors = append(data.Errors, fmt.Errorf("failed to unmarshal room file %s: %w", path.Join("rooms", name), err))
+			return
+		}
+		room.AreaID = area.ID
+		room.Area = &area
+		room.File = name
+		room.MobInstances = make(map[string]*MobInstance)
+		room.Characters = make(map[string]*Character)
+		data.Rooms = append(data.Rooms, &room)
+	})
+
+	// Load items (ItemBlueprints)
+	loadFilesFromDir(areaFS, "items", func(name string, b []byte) {
+		var item ItemBlueprint
+		if err := yaml.Unmarshal(b, &item); err != nil {
+			data.Errors = append(data.Errors, fmt.Errorf("failed to unmarshal item file %s: %w", path.Join("items", name), err))
+			return
+		}
+		item.AreaID = area.ID
+		item.File = name
+		data.Items = append(data.Items, &item)
+	})
+
+	// Load mobs (MobBlueprints)
+	loadFilesFromDir(areaFS, "mobs", func(name string, b []byte) {
+		var modBlueprint MobBlueprint
+		if err := yaml.Unmarshal(b, &modBlueprint); err != nil {
+			data.Errors = append(data.Errors, fmt.Errorf("failed to unmarshal mob file %s: %w", path.Join("mobs", name), err))
+			return
+		}
+		modBlueprint.AreaID = area.ID
+		modBlueprint.File = name
+
+		// Add pointer to metatype
+		modBlueprint.Metatype = mgr.GetMetatype(modBlueprint.MetatypeID)
+		data.Mobs = append(data.Mobs, &modBlueprint)
+	})
+
+	return data, nil
+}
+
+// logErrors logs the files in the area that couldn't be parsed.
+func (d *areaData) logErrors() {
+	for _, err := range d.Errors {
+		slog.Error("Skipped area file",
+			slog.String("area", d.Area.Dir),
+			slog.Any("error", err))
+	}
+}
+
+// knownMobs returns the area's mobs that have a metatype, dropping the ones that don't.
+func (d *areaData) knownMobs() []*MobBlueprint {
+	mobs := make([]*MobBlueprint, 0, len(d.Mobs))
+	for _, mob := range d.Mobs {
+		if mob.Metatype == nil {
+			slog.Warn("Metatype not found",
+				slog.String("mob_blueprint_id", mob.ID),
+				slog.String("metatype_id", mob.MetatypeID))
+			continue
+		}
+		mobs = append(mobs, mob)
+	}
+
+	return mobs
+}
+
 func (mgr *EntityManager) loadAreasFromFS(areasFS fs.FS) {
 	start := time.Now()
 
@@ -62,151 +174,515 @@ func (mgr *EntityManager) loadAreasFromFS(areasFS fs.FS) {
 			continue
 		}
 
-		// Load the area manifest
-		manifestBytes, err := fs.ReadFile(areaFS, "manifest.yml")
+		data, err := mgr.readArea(areaFS, d.Name())
 		if err != nil {
-			slog.Error("failed reading manifest", "area", d.Name(), "error", err)
+			slog.Error("failed loading area", "area", d.Name(), "error", err)
 			continue
 		}
-		var area Area
-		if err := yaml.Unmarshal(manifestBytes, &area); err != nil {
-			slog.Error("failed to unmarshal manifest data", "area", d.Name(), "error", err)
+		data.logErrors()
+		slog.Info("Loaded area", "area", d.Name())
+
+		mgr.AddArea(data.Area)
+		for _, room := range data.Rooms {
+			mgr.AddRoom(room)
+		}
+		for _, item := range data.Items {
+			mgr.AddItemBlueprint(item)
+		}
+		for _, mob := range data.knownMobs() {
+			mgr.AddMobBlueprint(mob)
+		}
+	}
+
+	mgr.BuildRooms()
+	slog.Info("Loaded areas", "duration", time.Since(start))
+}
+
+// GetAreaDir returns the directory an area was loaded from.
+func (mgr *EntityManager) GetAreaDir(areaID string) (string, error) {
+	area := mgr.GetArea(areaID)
+	if area == nil {
+		return "", fmt.Errorf("area %q not found", areaID)
+	}
+
+	if area.Dir == "" {
+		return area.ID, nil
+	}
+
+	return area.Dir, nil
+}
+
+// ReloadArea reloads an area from its data files. Like ReloadAreaDir, it has to run on the game tick.
+func (mgr *EntityManager) ReloadArea(areaID string) error {
+	dir, err := mgr.GetAreaDir(areaID)
+	if err != nil {
+		return err
+	}
+
+	return mgr.ReloadAreaDir(dir)
+}
+
+// areaReload is an area directory waiting to be reloaded on the game tick.
+type areaReload struct {
+	dir  string
+	done chan error
+}
+
+// QueueAreaReload reloads the area in the directory on the next game tick, so the reload doesn't race the
+// pulses that walk the rooms and blueprints it refreshes. The result is sent on the returned channel.
+func (mgr *EntityManager) QueueAreaReload(dir string) <-chan error {
+	done := make(chan error, 1)
+
+	mgr.Lock()
+	mgr.pendingReloads = append(mgr.pendingReloads, areaReload{dir: dir, done: done})
+	mgr.Unlock()
+
+	return done
+}
+
+// PulseAreaReloads runs the area reloads queued since the last tick.
+func (mgr *EntityManager) PulseAreaReloads() {
+	mgr.Lock()
+	reloads := mgr.pendingReloads
+	mgr.pendingReloads = nil
+	mgr.Unlock()
+
+	for _, r := range reloads {
+		r.done <- mgr.ReloadAreaDir(r.dir)
+	}
+}
+
+// ReloadAreaDir reloads the area in the directory, refreshing the existing area, rooms and blueprints in
+// place so the characters and mobs in the rooms and the item and mob instances made from the blueprints
+// pick up the changes. New rooms are spawned and the exits into and out of the area are linked again.
+// It has to run on the game tick; anything else should use QueueAreaReload.
+func (mgr *EntityManager) ReloadAreaDir(dir string) error {
+	start := time.Now()
+
+	data, err := mgr.readArea(os.DirFS(filepath.Join(viper.GetString("data.areas_path"), dir)), dir)
+	if err != nil {
+		return err
+	}
+	data.logErrors()
+
+	area := mgr.GetArea(data.Area.ID)
+	if area == nil {
+		area = data.Area
+		mgr.AddArea(area)
+	} else {
+		area.Refresh(data.Area)
+	}
+
+	var newRooms []*Room
+	for _, loaded := range data.Rooms {
+		loaded.Area = area
+		if room := mgr.GetRoom(loaded.QualifiedID()); room != nil {
+			room.Refresh(loaded)
 			continue
 		}
-		slog.Info("Loaded area", "area", d.Name())
-		mgr.AddArea(&area)
+		mgr.AddRoom(loaded)
+		newRooms = append(newRooms, loaded)
+	}
+
+	for _, loaded := range data.Items {
+		if bp := mgr.GetItemBlueprintByID(loaded.QualifiedID()); bp != nil {
+			*bp = *loaded
+			continue
+		}
+		mgr.AddItemBlueprint(loaded)
+	}
+
+	mobs := data.knownMobs()
+	for _, loaded := range mobs {
+		if bp := mgr.GetMobBlueprintByID(loaded.QualifiedID()); bp != nil {
+			*bp = *loaded
+			continue
+		}
+		mgr.AddMobBlueprint(loaded)
+	}
+
+	for _, room := range mgr.sortedRooms() {
+		if mgr.exitsTouchArea(room, area.ID) {
+			mgr.linkRoomExits(room)
+		}
+	}
+	for _, room := range newRooms {
+		mgr.spawnRoom(room)
+	}
+
+	slog.Info("Reloaded area",
+		slog.String("area_id", area.ID),
+		slog.Int("rooms", len(data.Rooms)),
+		slog.Int("new_rooms", len(newRooms)),
+		slog.Int("items", len(data.Items)),
+		slog.Int("mobs", len(mobs)),
+		slog.Duration("took", time.Since(start)))
+
+	return nil
+}
+
+// WatchAreas reloads an area whenever one of its data files changes.
+func (mgr *EntityManager) WatchAreas() {
+	root := viper.GetString("data.areas_path")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Error("Error creating area watcher", slog.Any("error", err))
+		return
+	}
+	defer watcher.Close()
+
+	// fsnotify doesn't watch recursively, so add the areas directory and everything below it
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Error watching areas", slog.String("path", root), slog.Any("error", err))
+		return
+	}
+
+	slog.Info("Watching areas for changes", slog.String("path", root))
 
-		// Load rooms
-		loadFilesFromDir(areaFS, "rooms", func(data []byte) {
-			var room Room
-			if err := yaml.Unmarshal(data, &room); err != nil {
-				slog.Error("failed to unmarshal room data", "area", d.Name(), "error", err)
+	// Editors often write a file in several steps, so wait for an area's changes to settle before reloading
+	timers := make(map[string]*time.Timer)
+
+	for {
+		select {
+		case e, ok := <-watcher.Events:
+			if !ok {
 				return
 			}
-			room.MobInstances = make(map[string]*MobInstance)
-			room.Characters = make(map[string]*Character)
-			mgr.AddRoom(&room)
-		})
-
-		// Load items (ItemBlueprints)
-		loadFilesFromDir(areaFS, "items", func(data []byte) {
-			var item ItemBlueprint
-			if err := yaml.Unmarshal(data, &item); err != nil {
-				slog.Error("failed to unmarshal item data", "area", d.Name(), "error", err)
-				return
+			if e.Op == fsnotify.Chmod {
+				continue
 			}
-			mgr.AddItemBlueprint(&item)
-		})
-
-		// Load mobs (MobBlueprints)
-		loadFilesFromDir(areaFS, "mobs", func(data []byte) {
-			var modBlueprint MobBlueprint
-			if err := yaml.Unmarshal(data, &modBlueprint); err != nil {
-				slog.Error("failed to unmarshal mob data", "area", d.Name(), "error", err)
-				return
+
+			if e.Has(fsnotify.Create) {
+				if info, err := os.Stat(e.Name); err == nil && info.IsDir() {
+					if err := watcher.Add(e.Name); err != nil {
+						slog.Error("Error watching directory", slog.String("path", e.Name), slog.Any("error", err))
+					}
+					continue
+				}
 			}
 
-			// Add pointer to metatype
-			metatype := mgr.GetMetatype(modBlueprint.MetatypeID)
-			if metatype == nil {
-				slog.Warn("Metatype not found",
-					slog.String("mob_blueprint_id", modBlueprint.ID),
-					slog.String("metatype_id", modBlueprint.MetatypeID))
+			rel, err := filepath.Rel(root, e.Name)
+			if err != nil || !IsYAMLFile(e.Name) {
+				continue
+			}
+			parts := strings.Split(filepath.ToSlash(rel), "/")
+			if len(parts) < 2 {
+				continue
+			}
+
+			dir := parts[0]
+			if t, ok := timers[dir]; ok {
+				t.Stop()
+			}
+			timers[dir] = time.AfterFunc(AreaReloadDelay, func() {
+				slog.Debug("Area files changed",
+					slog.String("dir", dir))
+				if err := <-mgr.QueueAreaReload(dir); err != nil {
+					slog.Error("Error reloading area", slog.String("dir", dir), slog.Any("error", err))
+				}
+			})
+		case err, ok := <-watcher.Errors:
+			if !ok {
 				return
 			}
+			slog.Error("Area watcher error", slog.Any("error", err))
+		}
+	}
+}
 
-			modBlueprint.Metatype = metatype
+func (mgr *EntityManager) BuildRooms() {
+	slog.Info("Building rooms")
 
-			mgr.AddMobBlueprint(&modBlueprint)
-		})
+	for _, room := range mgr.sortedRooms() {
+		mgr.linkRoomExits(room)
 	}
 
-	mgr.BuildRooms()
-	slog.Info("Loaded areas", "duration", time.Since(start))
+	for _, room := range mgr.rooms.all() {
+		mgr.spawnRoom(room)
+	}
 }
 
-func (mgr *EntityManager) BuildRooms() {
-	slog.Info("Building rooms")
+// sortedRooms returns every room in order of their qualified IDs, so doors shared between rooms are linked
+// the same way every time.
+func (mgr *EntityManager) sortedRooms() []*Room {
+	return slices.SortedFunc(maps.Values(mgr.GetAllRooms()), func(a, b *Room) int {
+		return strings.Compare(a.QualifiedID(), b.QualifiedID())
+	})
+}
 
-	for _, room := range mgr.rooms {
-		// Build exits
-		for dir, exit := range room.Exits {
-			exit.Room = mgr.GetRoom(exit.RoomID)
+// exitsTouchArea reports whether the room is in the area or has an exit leading into it.
+func (mgr *EntityManager) exitsTouchArea(room *Room, areaID string) bool {
+	if strings.EqualFold(room.AreaID, areaID) {
+		return true
+	}
 
-			if exit.Room == nil {
-				slog.Warn("Exit room not found",
-					slog.String("room_id", room.ID),
-					slog.String("exit_dir", dir),
-					slog.String("exit_room_id", exit.RoomID))
-				// TODO: Do we need to remove the exit from the room?
-				continue
-			}
+	for _, exit := range room.Exits {
+		if to := mgr.ResolveRoom(room.AreaID, exit.RoomID); to != nil && strings.EqualFold(to.AreaID, areaID) {
+			return true
+		}
+	}
 
-			if exit.Door != nil {
-				exit.Room.Exits[ReverseDirection(dir)].Door = exit.Door
-			}
+	return false
+}
+
+// linkRoomExits points the room's exits at the rooms they lead to and shares each door with the exit on
+// the other side. One-way exits, and exits whose way back leads somewhere else, keep their door to
+// themselves.
+func (mgr *EntityManager) linkRoomExits(room *Room) {
+	for dir, exit := range room.Exits {
+		exit.Room = mgr.ResolveRoom(room.AreaID, exit.RoomID)
+
+		if exit.Room == nil {
+			slog.Warn("Exit room not found",
+				slog.String("room_id", room.ID),
+				slog.String("exit_dir", dir),
+				slog.String("exit_room_id", exit.RoomID))
+			// TODO: Do we need to remove the exit from the room?
+			continue
 		}
 
-		// Loop over the mobs we need to spawn into the room
-		for _, spawn := range room.Spawns {
-			quantity := spawn.Quantity
-			if spawn.Quantity == 0 {
-				quantity = 1
-			}
-			chance := spawn.Chance
-			if spawn.Chance == 0 {
-				chance = 100
+		if exit.Door == nil || exit.OneWay {
+			continue
+		}
+		if back, ok := exit.Room.Exits[ReverseDirection(dir)]; ok && back.RoomID != "" &&
+			mgr.ResolveRoom(exit.Room.AreaID, back.RoomID) == room {
+			back.Door = exit.Door
+		}
+	}
+}
+
+// spawnRoom spawns the room's items and mobs, only topping up spawns that still have instances in the room
+// so resets don't pile them up.
+func (mgr *EntityManager) spawnRoom(room *Room) {
+	// Loop over the mobs we need to spawn into the room
+	for _, spawn := range room.Spawns {
+		quantity := spawn.Quantity
+		if spawn.Quantity == 0 {
+			quantity = 1
+		}
+		chance := spawn.Chance
+		if spawn.Chance == 0 {
+			chance = 100
+		}
+
+		if spawn.ItemID != "" {
+			// Spawn an item into the room
+			bp := mgr.ResolveItemBlueprint(room.AreaID, spawn.ItemID)
+			if bp == nil {
+				slog.Warn("Item blueprint not found",
+					slog.String("room_id", room.ID),
+					slog.String("item_id", spawn.ItemID))
+				continue
 			}
 
-			if spawn.ItemID != "" {
-				// Spawn an item into the room
-				bp := mgr.GetItemBlueprintByID(spawn.ItemID)
-				if bp == nil {
-					slog.Warn("Item blueprint not found",
+			for range quantity - room.countItems(bp) {
+				if !RollChance(chance) {
+					continue
+				}
+
+				i := mgr.CreateItemInstanceFromBlueprint(bp)
+				if i == nil {
+					slog.Warn("Item instance not found",
 						slog.String("room_id", room.ID),
 						slog.String("item_id", spawn.ItemID))
 					continue
 				}
+				room.Inventory.Add(i)
+			}
+		} else if spawn.MobID != "" {
+			bp := mgr.ResolveMobBlueprint(room.AreaID, spawn.MobID)
+			if bp == nil {
+				slog.Warn("Mob blueprint not found",
+					slog.String("room_id", room.ID),
+					slog.String("mob_blueprint_id", spawn.MobID))
+				continue
+			}
 
-				for range quantity {
-					if !RollChance(chance) {
-						continue
-					}
-
-					i := mgr.CreateItemInstanceFromBlueprint(bp)
-					if i == nil {
-						slog.Warn("Item instance not found",
-							slog.String("room_id", room.ID),
-							slog.String("item_id", spawn.ItemID))
-						continue
-					}
-					room.Inventory.Add(i)
+			for range quantity - room.countMobs(bp) {
+				if !RollChance(chance) {
+					continue
 				}
-			} else if spawn.MobID != "" {
-				bp := mgr.GetMobBlueprintByID(spawn.MobID)
-				if bp == nil {
-					slog.Warn("Mob blueprint not found",
+
+				mob := mgr.CreateMobInstanceFromBlueprint(bp)
+				if mob == nil {
+					slog.Warn("Mob not found",
 						slog.String("room_id", room.ID),
-						slog.String("mob_blueprint_id", spawn.MobID))
+						slog.String("mob_id", spawn.MobID))
 					continue
 				}
 
-				for range quantity {
-					if !RollChance(chance) {
-						continue
-					}
+				room.AddMobInstance(mob)
+			}
+		}
+	}
+}
 
-					mob := mgr.CreateMobInstanceFromBlueprint(bp)
-					if mob == nil {
-						slog.Warn("Mob not found",
-							slog.String("room_id", room.ID),
-							slog.String("mob_id", spawn.MobID))
-						continue
-					}
+// ResetArea respawns whatever is missing from the area's rooms.
+func (mgr *EntityManager) ResetArea(areaID string) {
+	slog.Debug("Resetting area",
+		slog.String("area_id", areaID))
 
-					room.AddMobInstance(mob)
-				}
+	for _, room := range mgr.GetAreaRooms(areaID) {
+		mgr.spawnRoom(room)
+	}
+}
+
+// PulseAreas resets the areas that are due and sends ambient messages to the characters in areas that
+// have them. It's called on every game tick.
+func (mgr *EntityManager) PulseAreas(now time.Time) {
+	for _, area := range mgr.GetAllAreas() {
+		if area.ResetDue(now) {
+			mgr.ResetArea(area.ID)
+		}
+
+		rooms := mgr.GetAreaRooms(area.ID)
+		if !slices.ContainsFunc(rooms, (*Room).HasCharacters) {
+			continue
+		}
+
+		if msg := area.NextAmbientMessage(now); msg != "" {
+			for _, room := range rooms {
+				room.Broadcast(cfmt.Sprintf("{{%s}}::cyan"+CRLF, msg), nil)
 			}
 		}
 	}
 }
+
+// roomFile is the part of a room that is written back to its area, leaving out the room's runtime state.
+type roomFile struct {
+	ID          string           `yaml:"id"`
+	Title       string           `yaml:"title"`
+	Description string           `yaml:"description"`
+	Landmark    string           `yaml:"landmark,omitempty"`
+	Light       string           `yaml:"light,omitempty"`
+	Tags        []string         `yaml:"tags,omitempty"`
+	Bias        Bias             `yaml:"bias,omitempty"`
+	Exits       map[string]*Exit `yaml:"exits,omitempty"`
+	Corrdinates *Corrdinates     `yaml:"corrdinates,omitempty"`
+	Spawns      []RoomSpawn      `yaml:"spawns,omitempty"`
+}
+
+// authoredExits copies the room's exits with their doors in the state they were built with, not however
+// players have left them.
+func authoredExits(exits map[string]*Exit) map[string]*Exit {
+	if len(exits) == 0 {
+		return nil
+	}
+
+	authored := make(map[string]*Exit, len(exits))
+	for dir, exit := range exits {
+		e := *exit
+		if exit.Door != nil {
+			e.Door = exit.Door.Authored()
+		}
+		authored[dir] = &e
+	}
+
+	return authored
+}
+
+// GetAreaRooms returns the rooms that belong to the area.
+func (mgr *EntityManager) GetAreaRooms(areaID string) []*Room {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	var rooms []*Room
+	for _, r := range mgr.rooms.all() {
+		if strings.EqualFold(r.AreaID, areaID) {
+			rooms = append(rooms, r)
+		}
+	}
+
+	return rooms
+}
+
+// SaveArea writes the area's manifest, rooms, items and mobs back to the area's directory, one file per
+// entity, using the file each entity was loaded from.
+func (mgr *EntityManager) SaveArea(areaID string) error {
+	area := mgr.GetArea(areaID)
+	if area == nil {
+		return fmt.Errorf("area %q not found", areaID)
+	}
+
+	if area.Dir == "" {
+		area.Dir = area.ID
+	}
+	dir := filepath.Join(viper.GetString("data.areas_path"), area.Dir)
+
+	slog.Info("Saving area",
+		slog.String("area_id", area.ID),
+		slog.String("dir", dir))
+
+	for _, sub := range []string{"rooms", "items", "mobs"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := SaveYAML(filepath.Join(dir, AreasFilename), area); err != nil {
+		return err
+	}
+
+	for _, r := range mgr.GetAreaRooms(area.ID) {
+		if r.File == "" {
+			r.File = r.ID + ".yml"
+		}
+
+		r.RLock()
+		rf := &roomFile{
+			ID:          r.ID,
+			Title:       r.Title,
+			Description: r.Description,
+			Landmark:    r.Landmark,
+			Light:       r.Light,
+			Tags:        r.Tags,
+			Bias:        r.Bias,
+			Exits:       authoredExits(r.Exits),
+			Corrdinates: r.Corrdinates,
+			Spawns:      r.Spawns,
+		}
+		r.RUnlock()
+
+		if err := SaveYAML(filepath.Join(dir, "rooms", r.File), rf); err != nil {
+			return err
+		}
+	}
+
+	for _, bp := range mgr.GetAllItemBlueprints() {
+		if !strings.EqualFold(bp.AreaID, area.ID) {
+			continue
+		}
+		if bp.File == "" {
+			bp.File = bp.ID + ".yml"
+		}
+
+		if err := SaveYAML(filepath.Join(dir, "items", bp.File), bp); err != nil {
+			return err
+		}
+	}
+
+	for _, bp := range mgr.GetAllMobBlueprints() {
+		if !strings.EqualFold(bp.AreaID, area.ID) {
+			continue
+		}
+		if bp.File == "" {
+			bp.File = bp.ID + ".yml"
+		}
+
+		if err := SaveYAML(filepath.Join(dir, "mobs", bp.File), bp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAreaRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("data.areas_path", dir)
+	defer viper.Set("data.areas_path", nil)
+
+	mgr := NewEntityManager()
+	mgr.AddMetatype(&Metatype{ID: "human"})
+
+	area := &Area{ID: "test", Title: "Test Area", Dir: "test_area"}
+	mgr.AddArea(area)
+
+	hall := &Room{ID: "hall", AreaID: "test", Area: area, Title: "Hall", Description: "A long hall.", File: "hall.yaml",
+		Exits:  map[string]*Exit{"north": {RoomID: "study", Direction: "north", Door: &Door{}}},
+		Spawns: []RoomSpawn{{ItemID: "lamp"}, {MobID: "butler", Quantity: 2}},
+	}
+	study := &Room{ID: "study", AreaID: "test", Area: area, Title: "Study", Description: "A quiet study.",
+		Exits: map[string]*Exit{"south": {RoomID: "hall", Direction: "south"}},
+	}
+	mgr.AddRoom(hall)
+	mgr.AddRoom(study)
+	mgr.AddItemBlueprint(&ItemBlueprint{ID: "lamp", Name: "a lamp", AreaID: "test"})
+	mgr.AddItemBlueprint(&ItemBlueprint{ID: "elsewhere", Name: "a rock", AreaID: "other"})
+	butler := &MobBlueprint{AreaID: "test"}
+	butler.ID = "butler"
+	butler.Name = "a butler"
+	butler.MetatypeID = "human"
+	mgr.AddMobBlueprint(butler)
+
+	// The door is built closed, but a player has opened it since
+	door := hall.Exits["north"].Door
+	door.SetAuthoredState(true, false)
+	door.IsClosed = false
+
+	assert.NoError(t, mgr.SaveArea("test"))
+	assert.False(t, door.IsClosed, "saving leaves the live door alone")
+	assert.FileExists(t, filepath.Join(dir, "test_area", AreasFilename))
+	assert.FileExists(t, filepath.Join(dir, "test_area", "rooms", "hall.yaml"), "rooms keep the file they were loaded from")
+	assert.FileExists(t, filepath.Join(dir, "test_area", "rooms", "study.yml"))
+	assert.FileExists(t, filepath.Join(dir, "test_area", "items", "lamp.yml"))
+	assert.NoFileExists(t, filepath.Join(dir, "test_area", "items", "elsewhere.yml"), "items from other areas aren't saved")
+	assert.FileExists(t, filepath.Join(dir, "test_area", "mobs", "butler.yml"))
+
+	loaded := NewEntityManager()
+	loaded.AddMetatype(&Metatype{ID: "human"})
+	loaded.loadAreasFromFS(os.DirFS(dir))
+
+	assert.Equal(t, "Test Area", loaded.GetArea("test").Title)
+	room := loaded.GetRoom("hall")
+	if assert.NotNil(t, room) {
+		assert.Equal(t, "A long hall.", room.Description)
+		assert.Equal(t, "study", room.Exits["north"].RoomID)
+		assert.True(t, room.Exits["north"].Door.IsClosed, "doors are saved as they were built")
+		assert.Equal(t, room.Exits["north"].Door, loaded.GetRoom("study").Exits["south"].Door, "doors are shared by both sides")
+		assert.Len(t, room.Spawns, 2)
+		assert.Len(t, room.MobInstances, 2)
+	}
+	assert.NotNil(t, loaded.GetItemBlueprintByID("lamp"))
+	assert.NotNil(t, loaded.GetMobBlueprintByID("butler"))
+
+	assert.Error(t, mgr.SaveArea("missing"))
+}
+
+func writeTestAreaFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestReloadAreaDir(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("data.areas_path", dir)
+	defer viper.Set("data.areas_path", nil)
+
+	writeTestAreaFile(t, dir, "test/manifest.yml", "id: test\ntitle: Test\n")
+	writeTestAreaFile(t, dir, "test/rooms/hall.yml", "id: hall\ntitle: Hall\n")
+	writeTestAreaFile(t, dir, "test/items/lamp.yml", "id: lamp\nname: a lamp\n")
+
+	mgr := NewEntityManager()
+	mgr.loadAreasFromFS(os.DirFS(dir))
+
+	hall := mgr.GetRoom("hall")
+	area := mgr.GetArea("test")
+	char := newTestCharacter("Alice")
+	hall.AddCharacter(char)
+	lamp := mgr.CreateItemInstanceFromBlueprint(mgr.GetItemBlueprintByID("lamp"))
+	hall.Inventory.Add(lamp)
+
+	writeTestAreaFile(t, dir, "test/manifest.yml", "id: test\ntitle: Test Area\n")
+	writeTestAreaFile(t, dir, "test/rooms/hall.yml", "id: hall\ntitle: Great Hall\nexits:\n  north:\n    room_id: study\n")
+	writeTestAreaFile(t, dir, "test/rooms/study.yml", "id: study\ntitle: Study\nexits:\n  south:\n    room_id: hall\n")
+	writeTestAreaFile(t, dir, "test/items/lamp.yml", "id: lamp\nname: a brass lamp\n")
+
+	assert.NoError(t, mgr.ReloadArea("test"))
+
+	assert.Same(t, area, mgr.GetArea("test"), "the area is refreshed in place")
+	assert.Equal(t, "Test Area", area.Title)
+	assert.Same(t, hall, mgr.GetRoom("hall"), "rooms are refreshed in place")
+	assert.Equal(t, "Great Hall", hall.Title)
+	assert.Contains(t, hall.Characters, char.ID, "characters stay in the room")
+	assert.Len(t, hall.Inventory.Items, 1, "items stay in the room")
+	assert.Equal(t, "a brass lamp", lamp.Blueprint.Name, "item instances see the refreshed blueprint")
+
+	study := mgr.GetRoom("study")
+	if assert.NotNil(t, study, "new rooms are added") {
+		assert.Same(t, study, hall.Exits["north"].Room, "exits are linked to new rooms")
+		assert.Same(t, hall, study.Exits["south"].Room)
+		assert.Same(t, area, study.Area)
+	}
+
+	assert.Error(t, mgr.ReloadArea("missing"))
+
+	// Doors keep their live state across reloads unless they're built differently
+	writeTestAreaFile(t, dir, "test/rooms/hall.yml", "id: hall\ntitle: Great Hall\nexits:\n  north:\n    room_id: study\n    door:\n      is_closed: true\n")
+	assert.NoError(t, mgr.ReloadArea("test"))
+	assert.True(t, hall.Exits["north"].Door.IsClosed)
+	hall.Exits["north"].Door.IsClosed = false
+	assert.NoError(t, mgr.ReloadArea("test"))
+	assert.False(t, hall.Exits["north"].Door.IsClosed, "an opened door stays open")
+
+	writeTestAreaFile(t, dir, "test/rooms/hall.yml", "id: hall\ntitle: Great Hall\nexits:\n  north:\n    room_id: study\n    door:\n      is_closed: true\n      is_locked: true\n")
+	assert.NoError(t, mgr.ReloadArea("test"))
+	assert.True(t, hall.Exits["north"].Door.IsLocked, "a rebuilt door takes its new state")
+}
+
+func TestQueueAreaReload(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("data.areas_path", dir)
+	defer viper.Set("data.areas_path", nil)
+
+	writeTestAreaFile(t, dir, "test/manifest.yml", "id: test\ntitle: Test\n")
+	writeTestAreaFile(t, dir, "test/rooms/hall.yml", "id: hall\ntitle: Hall\n")
+	writeTestAreaFile(t, dir, "other/manifest.yml", "id: other\ntitle: Other\n")
+	writeTestAreaFile(t, dir, "other/rooms/porch.yml", "id: porch\ntitle: Porch\nexits:\n  north:\n    room_id: test:study\n")
+	writeTestAreaFile(t, dir, "other/rooms/shed.yml", "id: shed\ntitle: Shed\nexits:\n  east:\n    room_id: porch\n")
+
+	mgr := NewEntityManager()
+	mgr.loadAreasFromFS(os.DirFS(dir))
+	porch := mgr.GetRoom("porch")
+	assert.Nil(t, porch.Exits["north"].Room)
+
+	writeTestAreaFile(t, dir, "test/rooms/study.yml", "id: study\ntitle: Study\n")
+	done := mgr.QueueAreaReload("test")
+	assert.Nil(t, mgr.GetRoom("study"), "nothing is reloaded until the tick")
+
+	// A relinked exit would find the porch again, so clear it to see whether the shed was touched
+	shed := mgr.GetRoom("shed")
+	shed.Exits["east"].Room = nil
+
+	mgr.PulseAreaReloads()
+	assert.NoError(t, <-done)
+	assert.Same(t, mgr.GetRoom("study"), porch.Exits["north"].Room, "exits into the area are linked again")
+	assert.Nil(t, shed.Exits["east"].Room, "exits that don't touch the area are left alone")
+
+	done = mgr.QueueAreaReload("missing")
+	mgr.PulseAreaReloads()
+	assert.Error(t, <-done)
+}
+
+func TestResetAreaTopsUpSpawns(t *testing.T) {
+	mgr := NewEntityManager()
+	mgr.AddMetatype(&Metatype{ID: "human"})
+	mgr.AddItemBlueprint(&ItemBlueprint{ID: "lamp", Name: "a lamp", AreaID: "test"})
+	butler := &MobBlueprint{AreaID: "test"}
+	butler.ID = "butler"
+	butler.Name = "a butler"
+	butler.MetatypeID = "human"
+	mgr.AddMobBlueprint(butler)
+
+	hall := &Room{ID: "hall", AreaID: "test", Inventory: NewInventory(), MobInstances: make(map[string]*MobInstance),
+		Spawns: []RoomSpawn{{ItemID: "lamp", Quantity: 2}, {MobID: "butler"}},
+	}
+	mgr.AddRoom(hall)
+
+	mgr.ResetArea("test")
+	assert.Len(t, hall.Inventory.Items, 2)
+	assert.Len(t, hall.MobInstances, 1)
+
+	hall.Inventory.Items = hall.Inventory.Items[:1]
+	mgr.ResetArea("test")
+	assert.Len(t, hall.Inventory.Items, 2, "only the missing lamp is respawned")
+	assert.Len(t, hall.MobInstances, 1)
+}
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"log/slog"
 
 	"github.com/google/uuid"
@@ -51,7 +52,7 @@ func (mgr *EntityManager) GetAllItemBlueprints() map[string]*ItemBlueprint {
 	mgr.RLock()
 	defer mgr.RUnlock()
 
-	return mgr.itemsBlueprints
+	return mgr.itemsBlueprints.all()
 }
 
 func (mgr *EntityManager) AddItemBlueprint(i *ItemBlueprint) {
@@ -59,30 +60,47 @@ func (mgr *EntityManager) AddItemBlueprint(i *ItemBlueprint) {
 	defer mgr.Unlock()
 
 	slog.Debug("Adding item blueprint",
-		slog.String("item_id", i.ID))
+		slog.String("item_id", i.QualifiedID()))
 
-	if _, ok := mgr.itemsBlueprints[i.ID]; ok {
+	if !mgr.itemsBlueprints.add(i.AreaID, i.ID, i) {
 		slog.Warn("Item blueprint already exists",
-			slog.String("item_id", i.ID))
+			slog.String("item_id", i.QualifiedID()))
 		return
 	}
 
-	mgr.itemsBlueprints[i.ID] = i
+	if others := mgr.itemsBlueprints.areas(i.ID); len(others) > 1 {
+		slog.Debug("Item blueprint ID is used by more than one area",
+			slog.String("item_id", i.ID),
+			slog.Any("items", others))
+	}
 }
 
+// GetItemBlueprintByID returns the item blueprint with an area-qualified ID ("limbo:small_rock") or a bare
+// ID used by only one area.
 func (mgr *EntityManager) GetItemBlueprintByID(id string) *ItemBlueprint {
+	return mgr.ResolveItemBlueprint("", id)
+}
+
+// ResolveItemBlueprint returns the item blueprint a reference made from within an area points to,
+// preferring the area's own item for bare IDs.
+func (mgr *EntityManager) ResolveItemBlueprint(areaID, ref string) *ItemBlueprint {
 	mgr.RLock()
 	defer mgr.RUnlock()
 
-	return mgr.itemsBlueprints[id]
+	bp, err := mgr.itemsBlueprints.lookup(areaID, ref)
+	if errors.Is(err, ErrReferenceAmbiguous) {
+		slog.Warn("Ambiguous item blueprint reference",
+			slog.String("area_id", areaID),
+			slog.String("item_blueprint_id", ref),
+			slog.Any("items", mgr.itemsBlueprints.areas(ref)))
+	}
+
+	return bp
 }
 
 func (mgr *EntityManager) GetItemBlueprintByInstance(item *ItemInstance) *ItemBlueprint {
-	mgr.RLock()
-	defer mgr.RUnlock()
-
-	bp, ok := mgr.itemsBlueprints[item.BlueprintID]
-	if !ok {
+	bp := mgr.GetItemBlueprintByID(item.BlueprintID)
+	if bp == nil {
 		slog.Error("Item blueprint not found",
 			slog.String("item_blueprint_id", item.BlueprintID))
 		return nil
@@ -92,9 +110,6 @@ func (mgr *EntityManager) GetItemBlueprintByInstance(item *ItemInstance) *ItemBl
 }
 
 func (mgr *EntityManager) CreateItemInstanceFromBlueprintID(id string) *ItemInstance {
-	mgr.RLock()
-	defer mgr.RUnlock()
-
 	bp := mgr.GetItemBlueprintByID(id)
 	if bp == nil {
 		slog.Error("Item blueprint not found",
@@ -109,7 +124,7 @@ func (mgr *EntityManager) CreateItemInstanceFromBlueprint(bp *ItemBlueprint) *It
 
 	var itemInstance ItemInstance
 	itemInstance.InstanceID = uuid.New().String()
-	itemInstance.BlueprintID = bp.ID
+	itemInstance.BlueprintID = bp.QualifiedID()
 	itemInstance.Blueprint = bp
 	itemInstance.Attachments = bp.Attachments
 
@@ -117,11 +132,8 @@ func (mgr *EntityManager) CreateItemInstanceFromBlueprint(bp *ItemBlueprint) *It
 }
 
 func (mgr *EntityManager) GetItemBlueprint(id string) *ItemBlueprint {
-	mgr.RLock()
-	defer mgr.RUnlock()
-
-	bp, ok := mgr.itemsBlueprints[id]
-	if !ok {
+	bp := mgr.GetItemBlueprintByID(id)
+	if bp == nil {
 		slog.Error("Item blueprint not found",
 			slog.String("item_blueprint_id", id))
 		return nil
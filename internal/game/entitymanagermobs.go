@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"log/slog"
 
 	"github.com/google/uuid"
@@ -51,48 +52,98 @@ func (mgr *EntityManager) GetAllMobBlueprints() map[string]*MobBlueprint {
 	mgr.RLock()
 	defer mgr.RUnlock()
 
-	return mgr.mobBlueprints
+	return mgr.mobBlueprints.all()
 }
 
 func (mgr *EntityManager) AddMobBlueprint(m *MobBlueprint) {
 	mgr.Lock()
 	defer mgr.Unlock()
 
-	if _, ok := mgr.mobBlueprints[m.ID]; ok {
+	if !mgr.mobBlueprints.add(m.AreaID, m.ID, m) {
 		slog.Warn("Mob blueprint already exists",
-			slog.String("mob_blueprint_id", m.ID))
+			slog.String("mob_blueprint_id", m.QualifiedID()))
 		return
 	}
 
-	mgr.mobBlueprints[m.ID] = m
+	if others := mgr.mobBlueprints.areas(m.ID); len(others) > 1 {
+		slog.Debug("Mob blueprint ID is used by more than one area",
+			slog.String("mob_blueprint_id", m.ID),
+			slog.Any("mobs", others))
+	}
 }
 
 func (mgr *EntityManager) RemoveMobBlueprint(m *MobBlueprint) {
 	mgr.Lock()
 	defer mgr.Unlock()
 
-	if _, ok := mgr.mobBlueprints[m.ID]; !ok {
-		slog.Warn("Mob blueprint not found",
-			slog.String("mob_blueprint_id", m.ID))
-		return
+	mgr.mobBlueprints.remove(m.AreaID, m.ID)
+}
+
+// PulseMobs moves the mobs that have a destination one step closer to it, forgetting the destination once
+// they arrive or can't find a way there. Mobs on patrol head for the next room of their patrol whenever they
+// have nowhere else to go. It's called on every game tick.
+func (mgr *EntityManager) PulseMobs() {
+	mgr.RLock()
+	var walking []*MobInstance
+	for _, m := range mgr.mobInstances {
+		if m.Room != nil && (m.Destination != nil || (m.Blueprint != nil && len(m.Blueprint.Patrol) > 0)) {
+			walking = append(walking, m)
+		}
+	}
+	mgr.RUnlock()
+
+	for _, m := range walking {
+		if m.Destination == nil {
+			m.Destination = m.NextPatrolStop()
+		}
+		if m.Destination == nil || m.Room == m.Destination || !m.StepToward(m.Destination) {
+			m.Destination = nil
+		}
+	}
+}
+
+// NextPatrolStop returns the next room of the mob's patrol that it isn't already in, nil if it has no
+// patrol. Patrol rooms are resolved relative to the mob's area.
+func (m *MobInstance) NextPatrolStop() *Room {
+	patrol := m.Blueprint.Patrol
+	for range patrol {
+		ref := patrol[m.patrolStop%len(patrol)]
+		m.patrolStop = (m.patrolStop + 1) % len(patrol)
+
+		if room := EntityMgr.ResolveRoom(m.Blueprint.AreaID, ref); room != nil && room != m.Room {
+			return room
+		}
 	}
 
-	delete(mgr.mobBlueprints, m.ID)
+	return nil
 }
 
+// GetMobBlueprintByID returns the mob blueprint with an area-qualified ID ("seattle:ork_thug_basic") or a
+// bare ID used by only one area.
 func (mgr *EntityManager) GetMobBlueprintByID(id string) *MobBlueprint {
+	return mgr.ResolveMobBlueprint("", id)
+}
+
+// ResolveMobBlueprint returns the mob blueprint a reference made from within an area points to,
+// preferring the area's own mob for bare IDs.
+func (mgr *EntityManager) ResolveMobBlueprint(areaID, ref string) *MobBlueprint {
 	mgr.RLock()
 	defer mgr.RUnlock()
 
-	return mgr.mobBlueprints[id]
+	bp, err := mgr.mobBlueprints.lookup(areaID, ref)
+	if errors.Is(err, ErrReferenceAmbiguous) {
+		slog.Warn("Ambiguous mob blueprint reference",
+			slog.String("area_id", areaID),
+			slog.String("mob_blueprint_id", ref),
+			slog.Any("mobs", mgr.mobBlueprints.areas(ref)))
+	}
+
+	return bp
 }
 
 func (mgr *EntityManager) GetMobBlueprintByInstance(mob *MobInstance) *MobBlueprint {
-	mgr.RLock()
-	defer mgr.RUnlock()
-
-	bp, ok := mgr.mobBlueprints[mob.BlueprintID]
-	if !ok {
+	bp := mgr.GetMobBlueprintByID(mob.BlueprintID)
+	if bp == nil {
 		slog.Error("Mob blueprint not found",
 			slog.String("mob_blueprint_id", mob.BlueprintID))
 		return nil
@@ -102,11 +153,8 @@ func (mgr *EntityManager) GetMobBlueprintByInstance(mob *MobInstance) *MobBluepr
 }
 
 func (mgr *EntityManager) CreateMobInstanceFromBlueprintID(id string) *MobInstance {
-	mgr.RLock()
-	defer mgr.RUnlock()
-
-	bp, ok := mgr.mobBlueprints[id]
-	if !ok {
+	bp := mgr.GetMobBlueprintByID(id)
+	if bp == nil {
 		slog.Error("Mob blueprint not found",
 			slog.String("mob_blueprint_id", id))
 		return nil
@@ -116,13 +164,10 @@ func (mgr *EntityManager) CreateMobInstanceFromBlueprintID(id string) *MobInstan
 }
 
 func (mgr *EntityManager) CreateMobInstanceFromBlueprint(bp *MobBlueprint) *MobInstance {
-	mgr.RLock()
-	defer mgr.RUnlock()
-
 	var mob MobInstance
 	mob.InstanceID = uuid.New().String()
 	mob.Blueprint = bp
-	mob.BlueprintID = bp.ID
+	mob.BlueprintID = bp.QualifiedID()
 	mob.GameEntityDynamic = NewGameEntityDynamic()
 
 	// Spawn items into the mob's inventory or equipment
@@ -145,7 +190,11 @@ func (mgr *EntityManager) CreateMobInstanceFromBlueprint(bp *MobBlueprint) *MobI
 					continue
 				}
 
-				item := mgr.CreateItemInstanceFromBlueprintID(spawn.ItemID)
+				// Spawned items are found relative to the mob's area
+				var item *ItemInstance
+				if itemBP := mgr.ResolveItemBlueprint(bp.AreaID, spawn.ItemID); itemBP != nil {
+					item = mgr.CreateItemInstanceFromBlueprint(itemBP)
+				}
 				if item == nil {
 					slog.Error("Item instance not found",
 						slog.String("mob_blueprint_id", mob.BlueprintID),
@@ -170,5 +219,7 @@ func (mgr *EntityManager) CreateMobInstanceFromBlueprint(bp *MobBlueprint) *MobI
 		}
 	}
 
+	mgr.AddMobInstance(&mob)
+
 	return &mob
 }
@@ -1,8 +1,8 @@
 package game
 
 import (
+	"errors"
 	"log/slog"
-	"strings"
 )
 
 // Room Functions
@@ -10,32 +10,51 @@ func (mgr *EntityManager) GetAllRooms() map[string]*Room {
 	mgr.RLock()
 	defer mgr.RUnlock()
 
-	return mgr.rooms
+	return mgr.rooms.all()
 }
 
 func (mgr *EntityManager) AddRoom(r *Room) {
 	mgr.Lock()
 	defer mgr.Unlock()
 
-	if _, ok := mgr.rooms[r.ID]; ok {
+	if !mgr.rooms.add(r.AreaID, r.ID, r) {
 		slog.Warn("Room already exists",
-			slog.String("room_id", r.ID))
+			slog.String("room_id", r.QualifiedID()))
 		return
 	}
 
-	mgr.rooms[r.ID] = r
+	if others := mgr.rooms.areas(r.ID); len(others) > 1 {
+		slog.Debug("Room ID is used by more than one area",
+			slog.String("room_id", r.ID),
+			slog.Any("rooms", others))
+	}
 }
 
+// GetRoom returns the room with an area-qualified ID ("limbo:the_void") or a bare ID used by only one area.
 func (mgr *EntityManager) GetRoom(id string) *Room {
+	return mgr.ResolveRoom("", id)
+}
+
+// ResolveRoom returns the room a reference made from within an area points to, preferring the area's own
+// room for bare IDs.
+func (mgr *EntityManager) ResolveRoom(areaID, ref string) *Room {
 	mgr.RLock()
 	defer mgr.RUnlock()
 
-	return mgr.rooms[strings.ToLower(id)]
+	r, err := mgr.rooms.lookup(areaID, ref)
+	if errors.Is(err, ErrReferenceAmbiguous) {
+		slog.Warn("Ambiguous room reference",
+			slog.String("area_id", areaID),
+			slog.String("room_id", ref),
+			slog.Any("rooms", mgr.rooms.areas(ref)))
+	}
+
+	return r
 }
 
 func (mgr *EntityManager) RemoveRoom(r *Room) {
 	mgr.Lock()
 	defer mgr.Unlock()
 
-	delete(mgr.rooms, r.ID)
+	mgr.rooms.remove(r.AreaID, r.ID)
 }
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -28,6 +30,35 @@ func (mgr *EntityManager) RemoveSkillBlueprint(s *SkillBlueprint) {
 	delete(mgr.skills, s.ID)
 }
 
+// GetLanguages returns the language skill blueprints sorted by name.
+func (mgr *EntityManager) GetLanguages() []*SkillBlueprint {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	var languages []*SkillBlueprint
+	for _, bp := range mgr.skills {
+		if bp.Type == SkillTypeLanguage {
+			languages = append(languages, bp)
+		}
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Name < languages[j].Name
+	})
+
+	return languages
+}
+
+// FindLanguage returns the language skill blueprint matching the ID or name.
+func (mgr *EntityManager) FindLanguage(name string) *SkillBlueprint {
+	for _, bp := range mgr.GetLanguages() {
+		if strings.EqualFold(bp.ID, name) || strings.EqualFold(bp.Name, name) {
+			return bp
+		}
+	}
+
+	return nil
+}
+
 func (mgr *EntityManager) CreateSkillInstanceFromBlueprintID(id string, rating int, specialization string) *Skill {
 	if bp := mgr.GetSkillBlueprint(id); bp != nil {
 		return mgr.CreateSkillInstanceFromBlueprint(bp, rating, specialization)
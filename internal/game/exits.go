@@ -0,0 +1,197 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultSearchDifficulty  = 2 // Perception hits needed to find a hidden exit without its own difficulty
+	PassivePerceptionPenalty = 2 // Dice lost noticing hidden exits without searching for them
+	SkillPerception          = "perception"
+	SkillGymnastics          = "gymnastics"
+	SpecializationClimbing   = "Climbing"
+	SpecializationBonus      = 2
+)
+
+// climbDifficulties are the climbing hits needed for exit types that have to be climbed.
+var climbDifficulties = map[string]int{
+	ExitTypeLadder: 1,
+	ExitTypeRope:   2,
+}
+
+// exitKey identifies one of a room's exits for the exits a character has found.
+func exitKey(room *Room, dir string) string {
+	return room.QualifiedID() + "/" + dir
+}
+
+// VisibleTo reports whether the character knows about the exit. Hidden exits have to be found first,
+// except by builders.
+func (e *Exit) VisibleTo(c *Character, room *Room) bool {
+	if !e.Hidden || c == nil {
+		return true
+	}
+
+	return c.HasFoundExit(room, e.Direction) || c.HasPermission(PermissionWorldEdit)
+}
+
+// Allows reports whether the character meets the exit's role, item and size requirements. Exits
+// restricted to a role or item are closed to mobs, which pass a nil character.
+func (e *Exit) Allows(c *Character) bool {
+	return e.checkAccess(c) == nil
+}
+
+// Traverse checks whether the character can use the exit, rolling any climbing test, and returns why not.
+func (e *Exit) Traverse(c *Character) error {
+	if err := e.checkAccess(c); err != nil {
+		return err
+	}
+
+	if difficulty := e.GetClimbDifficulty(); difficulty > 0 && c != nil {
+		pool := c.GetAgility() + skillPool(c, SkillGymnastics, SpecializationClimbing)
+		if hits := rollTest(pool); hits < difficulty {
+			return e.failure(fmt.Sprintf("You try to climb the %s but lose your grip.", e.Type))
+		}
+	}
+
+	return nil
+}
+
+func (e *Exit) checkAccess(c *Character) error {
+	if e.MinRole != "" {
+		g := PermissionMgr.GetGroup(e.MinRole)
+		if c == nil || g == nil || c.GetPermissionLevel() < g.Level {
+			return e.failure("You aren't allowed to go that way.")
+		}
+	}
+
+	if e.RequiredTag != "" && (c == nil || !c.CarriesTag(e.RequiredTag)) {
+		return e.failure(fmt.Sprintf("You need a %s to go that way.", strings.ReplaceAll(e.RequiredTag, "_", " ")))
+	}
+
+	if e.MaxSize != "" && c != nil {
+		if m := EntityMgr.GetMetatype(c.MetatypeID); m != nil && m.SizeRank() > MetatypeSizeRank(e.MaxSize) {
+			return e.failure("You're too big to fit through.")
+		}
+	}
+
+	return nil
+}
+
+// failure returns the exit's own failure message if it has one, otherwise the default.
+func (e *Exit) failure(msg string) error {
+	if e.FailMessage != "" {
+		return errors.New(e.FailMessage)
+	}
+
+	return errors.New(msg)
+}
+
+// GetClimbDifficulty returns the climbing hits needed to use the exit, 0 if it doesn't need climbing.
+func (e *Exit) GetClimbDifficulty() int {
+	if e.ClimbDifficulty > 0 {
+		return e.ClimbDifficulty
+	}
+
+	return climbDifficulties[e.Type]
+}
+
+// GetSearchDifficulty returns the perception hits needed to find the hidden exit.
+func (e *Exit) GetSearchDifficulty() int {
+	if e.SearchDifficulty > 0 {
+		return e.SearchDifficulty
+	}
+
+	return DefaultSearchDifficulty
+}
+
+// GetVisibleExit returns the exit in the direction if the character knows about it.
+func (r *Room) GetVisibleExit(c *Character, dir string) (*Exit, bool) {
+	exit, ok := r.Exits[dir]
+	if !ok || !exit.VisibleTo(c, r) {
+		return nil, false
+	}
+
+	return exit, true
+}
+
+// HasFoundExit reports whether the character has found the room's hidden exit.
+func (c *Character) HasFoundExit(room *Room, dir string) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.foundExits[exitKey(room, dir)]
+}
+
+// FindExit remembers that the character has found the room's hidden exit.
+func (c *Character) FindExit(room *Room, dir string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.foundExits == nil {
+		c.foundExits = make(map[string]bool)
+	}
+	c.foundExits[exitKey(room, dir)] = true
+}
+
+// SearchForExits rolls a Perception test for each hidden exit in the room the character hasn't found,
+// returning the directions found. Passive searches, made on entering a room, roll fewer dice, and poor
+// light and weather make both harder.
+func (c *Character) SearchForExits(room *Room, passive bool) []string {
+	pool := c.GetIntuition() + skillPool(c, SkillPerception, "") + c.PerceptionModifier(room)
+	if passive {
+		pool -= PassivePerceptionPenalty
+	}
+
+	var found []string
+	for _, dir := range pathDirections {
+		exit, ok := room.Exits[dir]
+		if !ok || exit.VisibleTo(c, room) {
+			continue
+		}
+		if rollTest(pool) >= exit.GetSearchDifficulty() {
+			c.FindExit(room, dir)
+			found = append(found, dir)
+		}
+	}
+
+	return found
+}
+
+// CarriesTag reports whether the character is carrying or wearing an item with the tag.
+func (c *Character) CarriesTag(tag string) bool {
+	items := append([]*ItemInstance{}, c.Inventory.Items...)
+	for _, item := range c.Equipment.Slots {
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		if bp := EntityMgr.GetItemBlueprintByInstance(item); bp != nil && HasAnyTag(bp.Tags, []string{tag}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skillPool returns the character's rating in a skill, with the specialization bonus if they have it.
+func skillPool(c *Character, skillID, specialization string) int {
+	skill := c.GetSkill(skillID)
+	if skill == nil {
+		return 0
+	}
+
+	pool := skill.Rating
+	if specialization != "" && strings.EqualFold(skill.Specialization, specialization) {
+		pool += SpecializationBonus
+	}
+
+	return pool
+}
+
+// rollTest rolls a dice pool, which can't go below zero, and returns the hits.
+var rollTest = func(pool int) int {
+	hits, _, _ := RollDice(max(pool, 0))
+	return hits
+}
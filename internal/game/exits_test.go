@@ -0,0 +1,113 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withTestEntityManager(t *testing.T) {
+	previous := EntityMgr
+	EntityMgr = NewEntityManager()
+	t.Cleanup(func() { EntityMgr = previous })
+}
+
+func withTestRolls(t *testing.T, hits int) {
+	previous := rollTest
+	rollTest = func(int) int { return hits }
+	t.Cleanup(func() { rollTest = previous })
+}
+
+func TestHiddenExits(t *testing.T) {
+	withTestPermissionGroups(t)
+	PermissionMgr.GetGroup("admin").Permissions = []string{PermissionWorldEdit}
+
+	hall := &Room{ID: "hall"}
+	closet := &Room{ID: "closet"}
+	linkTestRooms(hall, "north", closet)
+	exit := hall.Exits["north"]
+	exit.Hidden = true
+	exit.SearchDifficulty = 3
+
+	player := newTestCharacter("Bob")
+	admin := newTestCharacter("Alice")
+	admin.Role = "admin"
+
+	_, ok := hall.GetVisibleExit(player, "north")
+	assert.False(t, ok)
+	_, ok = hall.GetVisibleExit(admin, "north")
+	assert.True(t, ok, "builders see hidden exits")
+	_, ok = hall.GetVisibleExit(nil, "north")
+	assert.True(t, ok, "mobs know their way around")
+
+	_, err := FindPath(hall, closet, PathOptions{Character: player})
+	assert.ErrorIs(t, err, ErrNoPath)
+
+	withTestRolls(t, 2)
+	assert.Empty(t, player.SearchForExits(hall, false))
+
+	withTestRolls(t, 3)
+	assert.Equal(t, []string{"north"}, player.SearchForExits(hall, false))
+	_, ok = hall.GetVisibleExit(player, "north")
+	assert.True(t, ok)
+	assert.Empty(t, player.SearchForExits(hall, false), "found exits aren't found again")
+}
+
+func TestExitRequirements(t *testing.T) {
+	withTestPermissionGroups(t)
+	withTestEntityManager(t)
+
+	EntityMgr.AddMetatype(&Metatype{ID: "troll", Size: MetatypeSizeLarge})
+	EntityMgr.AddMetatype(&Metatype{ID: "dwarf", Size: MetatypeSizeSmall})
+	keycard := &ItemBlueprint{ID: "keycard", Name: "a keycard", Tags: []string{"Keycard"}}
+	EntityMgr.AddItemBlueprint(keycard)
+
+	troll := newTestCharacter("Bob")
+	troll.MetatypeID = "troll"
+	dwarf := newTestCharacter("Alice")
+	dwarf.MetatypeID = "dwarf"
+
+	duct := &Exit{MaxSize: MetatypeSizeMedium}
+	assert.EqualError(t, duct.Traverse(troll), "You're too big to fit through.")
+	assert.NoError(t, duct.Traverse(dwarf))
+	assert.True(t, duct.Allows(nil))
+
+	lab := &Exit{RequiredTag: "keycard", FailMessage: "The scanner flashes red."}
+	assert.EqualError(t, lab.Traverse(dwarf), "The scanner flashes red.")
+	assert.False(t, lab.Allows(nil))
+	dwarf.Inventory.Add(EntityMgr.CreateItemInstanceFromBlueprint(keycard))
+	assert.NoError(t, lab.Traverse(dwarf))
+
+	rope := &Exit{Type: ExitTypeRope}
+	assert.Equal(t, 2, rope.GetClimbDifficulty())
+	withTestRolls(t, 1)
+	assert.EqualError(t, rope.Traverse(dwarf), "You try to climb the rope but lose your grip.")
+	withTestRolls(t, 2)
+	assert.NoError(t, rope.Traverse(dwarf))
+}
+
+func TestLinkOneWayExits(t *testing.T) {
+	mgr := NewEntityManager()
+	chute := &Room{ID: "chute", Exits: map[string]*Exit{
+		"down": {RoomID: "cellar", Direction: "down", OneWay: true, Door: &Door{IsClosed: true}},
+	}}
+	cellar := &Room{ID: "cellar", Exits: map[string]*Exit{
+		"up": {RoomID: "chute", Direction: "up"},
+	}}
+	hall := &Room{ID: "hall", Exits: map[string]*Exit{
+		"east": {RoomID: "study", Direction: "east", Door: &Door{}},
+	}}
+	study := &Room{ID: "study", Exits: map[string]*Exit{
+		"west": {RoomID: "hall", Direction: "west"},
+	}}
+	for _, r := range []*Room{chute, cellar, hall, study} {
+		mgr.AddRoom(r)
+	}
+	for _, r := range []*Room{chute, cellar, hall, study} {
+		mgr.linkRoomExits(r)
+	}
+
+	assert.Same(t, cellar, chute.Exits["down"].Room)
+	assert.Nil(t, cellar.Exits["up"].Door, "one-way exits keep their door")
+	assert.Same(t, hall.Exits["east"].Door, study.Exits["west"].Door)
+}
@@ -1,6 +1,8 @@
 package game
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"slices"
 	"strings"
@@ -28,6 +30,7 @@ type GameEntityDynamic struct {
 	Equipment             Equipment           `yaml:"equipment,omitempty"`
 	Qualtities            map[string]*Quality `yaml:"qualities,omitempty"`
 	Skills                map[string]*Skill   `yaml:"skills,omitempty"`
+	NativeLanguages       []string            `yaml:"native_languages,omitempty"`
 	CharacterDispositions map[string]string   `yaml:"character_dispositions,omitempty"`
 	Labels                []string            `yaml:"labels,omitempty"`
 }
@@ -146,7 +149,29 @@ func (ged *GameEntityDynamic) RemoveLabel(label string) {
 }
 
 // Validate the game entity dynamic
-// TODO: Implement validation
 func (ged *GameEntityDynamic) Validate() error {
-	return nil
+	var errs []error
+
+	if ged.Edge < 0 {
+		errs = append(errs, errors.New("edge can't be negative"))
+	}
+	if ged.PhysicalDamage < 0 || ged.StunDamage < 0 || ged.OverflowDamage < 0 {
+		errs = append(errs, errors.New("damage can't be negative"))
+	}
+	if ged.PositionState != "" && !slices.Contains([]string{PositionStanding, PositionSitting, PositionKneeling,
+		PositionLying, PositionProne, PositionCrouching, PositionResting, PositionSleeping, PositionUnconscious}, ged.PositionState) {
+		errs = append(errs, fmt.Errorf("unknown position_state %q", ged.PositionState))
+	}
+	for id, skill := range ged.Skills {
+		if skill == nil || skill.Rating < 0 {
+			errs = append(errs, fmt.Errorf("skill %q needs a rating of zero or more", id))
+		}
+	}
+	for id, quality := range ged.Qualtities {
+		if quality == nil || quality.Rating < 0 {
+			errs = append(errs, fmt.Errorf("quality %q needs a rating of zero or more", id))
+		}
+	}
+
+	return errors.Join(errs...)
 }
@@ -1,5 +1,14 @@
 package game
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Jasrags/NewMUD/pluralizer"
+)
+
 const (
 	SexMale      = "Male"
 	SexFemale    = "Female"
@@ -34,8 +43,104 @@ func (g *GameEntityInformation) GetGeneralDisposition() string {
 	return g.GeneralDisposition
 }
 
+// usesPluralPronouns reports whether the entity is referred to as they/them, which is the case for
+// Non-Binary entities and those without a sex set.
+func (g *GameEntityInformation) usesPluralPronouns() bool {
+	return g.sex() != SexMale && g.sex() != SexFemale
+}
+
+// sex normalizes the entity's sex, as characters created before the menu stored the Sex constants have
+// it saved in lowercase.
+func (g *GameEntityInformation) sex() string {
+	switch strings.ToLower(g.Sex) {
+	case strings.ToLower(SexMale):
+		return SexMale
+	case strings.ToLower(SexFemale):
+		return SexFemale
+	default:
+		return SexNonBinary
+	}
+}
+
+// SubjectPronoun returns he, she or they.
+func (g *GameEntityInformation) SubjectPronoun() string {
+	switch g.sex() {
+	case SexMale:
+		return "he"
+	case SexFemale:
+		return "she"
+	default:
+		return "they"
+	}
+}
+
+// ObjectPronoun returns him, her or them.
+func (g *GameEntityInformation) ObjectPronoun() string {
+	switch g.sex() {
+	case SexMale:
+		return "him"
+	case SexFemale:
+		return "her"
+	default:
+		return "them"
+	}
+}
+
+// PossessivePronoun returns his, her or their.
+func (g *GameEntityInformation) PossessivePronoun() string {
+	switch g.sex() {
+	case SexMale:
+		return "his"
+	case SexFemale:
+		return "her"
+	default:
+		return "their"
+	}
+}
+
+// ReflexivePronoun returns himself, herself or themselves.
+func (g *GameEntityInformation) ReflexivePronoun() string {
+	switch g.sex() {
+	case SexMale:
+		return "himself"
+	case SexFemale:
+		return "herself"
+	default:
+		return "themselves"
+	}
+}
+
+// ConjugateVerb makes a third person singular verb ("smiles", "is") agree with the entity's subject
+// pronoun, so they/them entities get "smile" and "are".
+func (g *GameEntityInformation) ConjugateVerb(verb string) string {
+	if g.usesPluralPronouns() {
+		return pluralizer.PluralizeVerb(verb)
+	}
+
+	return verb
+}
+
 // Validate the game entity information
-// TODO: Implement validation
 func (g *GameEntityInformation) Validate() error {
-	return nil
+	var errs []error
+
+	if g.ID == "" {
+		errs = append(errs, errors.New("id is required"))
+	}
+	if g.MetatypeID == "" {
+		errs = append(errs, errors.New("metatype_id is required"))
+	}
+	if g.Sex != "" && !slices.ContainsFunc([]string{SexMale, SexFemale, SexNonBinary}, func(sex string) bool {
+		return strings.EqualFold(sex, g.Sex)
+	}) {
+		errs = append(errs, fmt.Errorf("unknown sex %q", g.Sex))
+	}
+	if g.GeneralDisposition != "" && !slices.Contains([]string{DispositionFriendly, DispositionNeutral, DispositionAggressive}, g.GeneralDisposition) {
+		errs = append(errs, fmt.Errorf("unknown general_disposition %q", g.GeneralDisposition))
+	}
+	if g.Age < 0 || g.Height < 0 || g.Weight < 0 {
+		errs = append(errs, errors.New("age, height and weight can't be negative"))
+	}
+
+	return errors.Join(errs...)
 }
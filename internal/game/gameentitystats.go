@@ -106,6 +106,18 @@ func (ges *GameEntityStats) GetFatigueResistance() int {
 	return ges.GetBody()
 }
 
+// GetPhysicalConditionMax calculates and returns the boxes on the Physical Condition Monitor.
+// Formula: 8 + (BOD / 2) rounded up
+func (ges *GameEntityStats) GetPhysicalConditionMax() int {
+	return 8 + (ges.GetBody()+1)/2
+}
+
+// GetStunConditionMax calculates and returns the boxes on the Stun Condition Monitor.
+// Formula: 8 + (WIL / 2) rounded up
+func (ges *GameEntityStats) GetStunConditionMax() int {
+	return 8 + (ges.GetWillpower()+1)/2
+}
+
 func (ges *GameEntityStats) GetLiftCarry() int {
 	return ges.GetBody() * 10
 }
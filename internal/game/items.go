@@ -23,6 +23,9 @@ const (
 	ItemTypeJunk = "junk"
 	ItemTypeKey  = "key"
 
+	ItemTypeComlink = "comlink"
+	ItemTypeBoard   = "board"
+
 	ItemSubtypeNone  = "None"
 	ItemSubtypeMelee = "Melee"
 
@@ -132,6 +135,9 @@ type (
 		AmmoCapacity     int      `yaml:"ammo_capacity,omitempty"`
 		AmmoTypes        []string `yaml:"ammo_type,omitempty"`
 		ReloadType       string   `yaml:"reload_type,omitempty"`
+		// Source
+		AreaID string `yaml:"-"`
+		File   string `yaml:"-"`
 	}
 
 	// TODO: need to add the weight of attachments to the weight of the item
@@ -178,6 +184,11 @@ func (i *ItemInstance) FormatDetailed() string {
 	return wordwrap.String(sb.String(), 80)
 }
 
+// QualifiedID returns the blueprint's area-qualified ID.
+func (ib *ItemBlueprint) QualifiedID() string {
+	return QualifyID(ib.AreaID, ib.ID)
+}
+
 func (ib *ItemBlueprint) HasTags(searchTags ...string) bool {
 	for _, searchTag := range searchTags {
 		if !slices.Contains(ib.Tags, searchTag) {
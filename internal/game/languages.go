@@ -0,0 +1,118 @@
+package game
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/rand"
+)
+
+const (
+	LanguageDefault = "english"
+
+	// Native speakers understand everything, as do characters with a skill rating of LanguageFluentRating
+	LanguageNativeRating = 7
+	LanguageFluentRating = 6
+
+	QualityBilingual = "bilingual"
+
+	garbleConsonants = "bcdfghjklmnprstvwz"
+	garbleVowels     = "aeiou"
+)
+
+// GetNativeLanguages returns the entity's native languages, defaulting to LanguageDefault for entities
+// created before languages were tracked.
+func (ged *GameEntityDynamic) GetNativeLanguages() []string {
+	if len(ged.NativeLanguages) == 0 {
+		return []string{LanguageDefault}
+	}
+
+	return ged.NativeLanguages
+}
+
+// GetMaxNativeLanguages returns how many native languages the entity may have, one plus one for each
+// rating of the bilingual quality.
+func (ged *GameEntityDynamic) GetMaxNativeLanguages() int {
+	q := ged.GetQuality(QualityBilingual)
+	if q == nil {
+		return 1
+	}
+
+	return 1 + max(q.Rating, 1)
+}
+
+// GetLanguageRating returns how well the entity understands a language, LanguageNativeRating for native
+// languages, otherwise their language skill rating.
+func (ged *GameEntityDynamic) GetLanguageRating(languageID string) int {
+	if slices.Contains(ged.GetNativeLanguages(), languageID) {
+		return LanguageNativeRating
+	}
+
+	skill := ged.GetSkill(languageID)
+	if skill == nil {
+		return 0
+	}
+
+	return skill.Rating
+}
+
+// GetSpeakingLanguage returns the language the character is speaking, defaulting to their first native
+// language.
+func (c *Character) GetSpeakingLanguage() string {
+	if c.Language != "" {
+		return c.Language
+	}
+
+	return c.GetNativeLanguages()[0]
+}
+
+// GarbleText hides the words of a message the listener doesn't understand. Each word is understood with a
+// chance based on the listener's rating, and garbled words are replaced consistently so the same word
+// always sounds the same.
+func GarbleText(text string, rating int) string {
+	if rating >= LanguageFluentRating {
+		return text
+	}
+
+	words := strings.Fields(text)
+	for i, word := range words {
+		if rating > 0 && rand.Intn(LanguageFluentRating) < rating {
+			continue
+		}
+		words[i] = garbleWord(word)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// garbleWord replaces the letters of a word with nonsense syllables, keeping its case and punctuation.
+func garbleWord(word string) string {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(word)))
+	rng := rand.New(rand.NewSource(h.Sum64()))
+
+	var builder strings.Builder
+	vowel := false
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			builder.WriteRune(r)
+			continue
+		}
+
+		letters := garbleConsonants
+		if vowel {
+			letters = garbleVowels
+		}
+		vowel = !vowel
+
+		g := rune(letters[rng.Intn(len(letters))])
+		if unicode.IsUpper(r) {
+			g = unicode.ToUpper(g)
+		}
+		builder.WriteRune(g)
+	}
+
+	return builder.String()
+}
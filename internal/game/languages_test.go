@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGarbleText(t *testing.T) {
+	text := "Hello, chummer. The Johnson wants you."
+
+	assert.Equal(t, text, GarbleText(text, LanguageFluentRating))
+	assert.Equal(t, text, GarbleText(text, LanguageNativeRating))
+
+	garbled := GarbleText(text, 0)
+	assert.NotEqual(t, text, garbled)
+	assert.Len(t, garbled, len(text), "garbled words keep their length")
+	assert.Equal(t, garbled, GarbleText(text, 0), "words garble the same way every time")
+	assert.Equal(t, ',', rune(garbled[5]), "punctuation is kept")
+	assert.True(t, unicode.IsUpper(rune(garbled[0])), "capitalization is kept")
+}
+
+func TestLanguageRatings(t *testing.T) {
+	c := newTestCharacter("Alice")
+	assert.Equal(t, []string{LanguageDefault}, c.GetNativeLanguages())
+	assert.Equal(t, LanguageDefault, c.GetSpeakingLanguage())
+	assert.Equal(t, 1, c.GetMaxNativeLanguages())
+
+	c.NativeLanguages = []string{"japanese"}
+	c.Skills["orzet"] = &Skill{BlueprintID: "orzet", Rating: 3}
+	assert.Equal(t, LanguageNativeRating, c.GetLanguageRating("japanese"))
+	assert.Equal(t, 3, c.GetLanguageRating("orzet"))
+	assert.Equal(t, 0, c.GetLanguageRating(LanguageDefault))
+	assert.Equal(t, "japanese", c.GetSpeakingLanguage())
+
+	c.Qualtities[QualityBilingual] = &Quality{BlueprintID: QualityBilingual}
+	assert.Equal(t, 2, c.GetMaxNativeLanguages())
+
+	c.NativeLanguages = []string{"japanese", LanguageDefault}
+	assert.Empty(t, languageTag(c, LanguageDefault), "every native language goes untagged")
+	assert.Equal(t, " in a language you don't understand", languageTag(c, "sperethiel"))
+}
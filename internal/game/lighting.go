@@ -0,0 +1,112 @@
+package game
+
+import (
+	"slices"
+)
+
+const (
+	LightFull    = "full" // Default
+	LightPartial = "partial"
+	LightDim     = "dim"
+	LightDark    = "dark"
+
+	QualityLowLightVision      = "low_light_vision"
+	QualityThermographicVision = "thermographic_vision"
+
+	VisionModifierBlind         = -6 // Characters with this modifier or worse can't see at all
+	VisionModifierObscured      = -3 // Characters with this modifier or worse can't make out details
+	VisionModifierThermographic = -3 // Heat sources are still visible in total darkness
+)
+
+// LightLevels are the light levels a room can have from brightest to darkest.
+var LightLevels = []string{LightFull, LightPartial, LightDim, LightDark}
+
+// lightModifiers are the dice pool modifiers for tests that rely on sight at each light level.
+var lightModifiers = map[string]int{
+	LightFull:    0,
+	LightPartial: -1,
+	LightDim:     -3,
+	LightDark:    -6,
+}
+
+// Daylight returns the light outdoors at the game time: full light during the day, partial light at dawn
+// and dusk, and dim light from the moon and stars at night.
+func (t *GameTime) Daylight() string {
+	switch hour := t.CurrentHour(); {
+	case hour >= 7 && hour < 18:
+		return LightFull
+	case hour == 6 || hour == 18:
+		return LightPartial
+	default:
+		return LightDim
+	}
+}
+
+// IsOutdoors reports whether the room is outside and lit by the day and night.
+func (r *Room) IsOutdoors() bool {
+	return HasAnyTag(r.AllTags(), []string{RoomTagOutdoors})
+}
+
+// LightLevel returns the light in the room. Outdoor rooms are lit by the daylight or their own light,
+// whichever is brighter.
+func (r *Room) LightLevel() string {
+	light := r.Light
+	if !slices.Contains(LightLevels, light) {
+		light = LightFull
+		if r.IsOutdoors() {
+			light = LightDark
+		}
+	}
+
+	if r.IsOutdoors() {
+		return brighterLight(light, GameTimeMgr.Daylight())
+	}
+
+	return light
+}
+
+func brighterLight(a, b string) string {
+	if slices.Index(LightLevels, a) <= slices.Index(LightLevels, b) {
+		return a
+	}
+
+	return b
+}
+
+// HasVisionQuality reports whether the character has a vision quality from their metatype or their own
+// qualities.
+func (c *Character) HasVisionQuality(qualityID string) bool {
+	if c.GetQuality(qualityID) != nil {
+		return true
+	}
+
+	m := EntityMgr.GetMetatype(c.MetatypeID)
+	return m != nil && slices.Contains(m.Qualities, qualityID)
+}
+
+// VisionModifier returns the dice pool modifier for perception and combat tests that rely on sight in the
+// room. Low-light vision ignores partial and dim light, and thermographic vision still picks out heat
+// sources in total darkness.
+func (c *Character) VisionModifier(room *Room) int {
+	light := room.LightLevel()
+	mod := lightModifiers[light]
+
+	if light != LightDark && c.HasVisionQuality(QualityLowLightVision) {
+		mod = 0
+	}
+	if c.HasVisionQuality(QualityThermographicVision) {
+		mod = max(mod, VisionModifierThermographic)
+	}
+
+	return mod
+}
+
+// CanSeeIn reports whether the character can see anything in the room.
+func (c *Character) CanSeeIn(room *Room) bool {
+	return c.VisionModifier(room) > VisionModifierBlind
+}
+
+// CanMakeOut reports whether the character can see the room well enough to make out who and what is in it.
+func (c *Character) CanMakeOut(room *Room) bool {
+	return c.VisionModifier(room) > VisionModifierObscured
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withTestGameTime(t *testing.T, hour int) {
+	previous := GameTimeMgr
+	GameTimeMgr = NewGameTime()
+	GameTimeMgr.Minutes = hour * 60
+	t.Cleanup(func() { GameTimeMgr = previous })
+}
+
+func TestRoomLightLevel(t *testing.T) {
+	cellar := &Room{ID: "cellar", Light: LightDark}
+	hall := &Room{ID: "hall"}
+	street := &Room{ID: "street", Tags: []string{RoomTagOutdoors}, Light: LightPartial}
+	park := &Room{ID: "park", Tags: []string{RoomTagOutdoors}}
+
+	withTestGameTime(t, 12)
+	assert.Equal(t, LightDark, cellar.LightLevel())
+	assert.Equal(t, LightFull, hall.LightLevel())
+	assert.Equal(t, LightFull, street.LightLevel())
+	assert.Equal(t, LightFull, park.LightLevel())
+
+	withTestGameTime(t, 23)
+	assert.Equal(t, LightPartial, street.LightLevel(), "street lights are brighter than the night")
+	assert.Equal(t, LightDim, park.LightLevel())
+}
+
+func TestVisionModifier(t *testing.T) {
+	withTestEntityManager(t)
+	EntityMgr.AddMetatype(&Metatype{ID: "human"})
+	EntityMgr.AddMetatype(&Metatype{ID: "elf", Qualities: []string{QualityLowLightVision}})
+	EntityMgr.AddMetatype(&Metatype{ID: "troll", Qualities: []string{QualityThermographicVision}})
+
+	human := newTestCharacter("Bob")
+	human.MetatypeID = "human"
+	elf := newTestCharacter("Alice")
+	elf.MetatypeID = "elf"
+	troll := newTestCharacter("Carol")
+	troll.MetatypeID = "troll"
+
+	dim := &Room{ID: "dim", Light: LightDim}
+	dark := &Room{ID: "dark", Light: LightDark}
+
+	assert.Equal(t, -3, human.VisionModifier(dim))
+	assert.Equal(t, 0, elf.VisionModifier(dim))
+	assert.Equal(t, -3, troll.VisionModifier(dim))
+	assert.True(t, human.CanSeeIn(dim))
+	assert.False(t, human.CanMakeOut(dim))
+	assert.True(t, elf.CanMakeOut(dim))
+
+	assert.False(t, human.CanSeeIn(dark))
+	assert.False(t, elf.CanSeeIn(dark))
+	assert.True(t, troll.CanSeeIn(dark))
+}
+
+func TestRenderRoomInDarkness(t *testing.T) {
+	withTestEntityManager(t)
+	EntityMgr.AddMetatype(&Metatype{ID: "human", Name: "Human"})
+
+	cellar := &Room{ID: "cellar", Title: "Cellar", Description: "Dusty shelves line the walls.", Light: LightDark,
+		Characters: make(map[string]*Character)}
+	char := newTestCharacter("Bob")
+	char.MetatypeID = "human"
+	char.SetRoom(cellar)
+	cellar.AddCharacter(char)
+
+	out := stripANSI(RenderRoom(nil, char, nil))
+	assert.Contains(t, out, "too dark to see")
+	assert.NotContains(t, out, "Cellar")
+	assert.NotContains(t, out, "Dusty shelves")
+
+	cellar.Light = LightDim
+	other := newTestCharacter("Alice")
+	other.MetatypeID = "human"
+	cellar.AddCharacter(other)
+
+	out = stripANSI(RenderRoom(nil, char, nil))
+	assert.Contains(t, out, "Dusty shelves")
+	assert.Contains(t, out, "shadowy figure")
+	assert.NotContains(t, out, "Alice")
+}
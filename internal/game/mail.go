@@ -0,0 +1,71 @@
+package game
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrMailRecipientNotFound = errors.New("there is no character by that name")
+	ErrMailboxFull           = errors.New("their mailbox is full")
+)
+
+type MailMessage struct {
+	From    string    `yaml:"from"`
+	To      string    `yaml:"to"`
+	Subject string    `yaml:"subject"`
+	Body    string    `yaml:"body"`
+	SentAt  time.Time `yaml:"sent_at"`
+	Read    bool      `yaml:"read"`
+}
+
+// SendMail delivers a message to the mailbox of the account that owns the named character, notifying the
+// recipient if they are online. Mail from a character the recipient is ignoring is silently dropped.
+func SendMail(sender *Character, recipientName, subject, body string) error {
+	recipient := CharacterMgr.GetCharacterByName(recipientName)
+	if recipient == nil {
+		return ErrMailRecipientNotFound
+	}
+
+	account := AccountMgr.GetByCharacterName(recipient.Name)
+	if account == nil {
+		return ErrMailRecipientNotFound
+	}
+
+	if !sender.HasPermission(PermissionBypassIgnore) && account.IsIgnoring(sender) {
+		slog.Debug("Dropping mail from ignored character",
+			slog.String("character_name", sender.Name),
+			slog.String("recipient_name", recipient.Name))
+		return nil
+	}
+
+	maxMail := viper.GetInt("server.max_mailbox_size")
+	account.RLock()
+	count := len(account.Mail)
+	account.RUnlock()
+	if maxMail > 0 && count >= maxMail {
+		return ErrMailboxFull
+	}
+
+	account.AddMail(&MailMessage{
+		From:    sender.Name,
+		To:      recipient.Name,
+		Subject: subject,
+		Body:    body,
+		SentAt:  time.Now(),
+	})
+
+	if err := account.Save(); err != nil {
+		return err
+	}
+
+	if CharacterMgr.IsOnline(recipient.Name) {
+		recipient.Send(cfmt.Sprintf("{{You have new mail from %s.}}::cyan"+CRLF, sender.Name))
+	}
+
+	return nil
+}
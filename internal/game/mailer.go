@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+const (
+	MailerDriverFile = "file"
+	MailerDriverLog  = "log"
+)
+
+var (
+	Mailer MailSender = &FileMailer{}
+)
+
+// MailSender delivers out of game email such as password reset tokens.
+type MailSender interface {
+	Send(to, subject, body string) error
+}
+
+// NewMailerFromConfig returns the mailer selected by mail.driver, defaulting to the file mailer.
+func NewMailerFromConfig() MailSender {
+	switch viper.GetString("mail.driver") {
+	case MailerDriverLog:
+		return &LogMailer{}
+	default:
+		return &FileMailer{}
+	}
+}
+
+// FileMailer writes each message as a file in a maildir style spool for local testing.
+type FileMailer struct{}
+
+func (m *FileMailer) Send(to, subject, body string) error {
+	spoolPath := filepath.Join(viper.GetString("mail.spool_path"), "new")
+	if err := os.MkdirAll(spoolPath, 0700); err != nil {
+		return err
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", viper.GetString("mail.from"))
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	fileName := fmt.Sprintf("%d.%s.eml", time.Now().UnixNano(), uuid.New().String())
+	filePath := filepath.Join(spoolPath, fileName)
+
+	slog.Info("Writing mail to spool",
+		slog.String("to", to),
+		slog.String("subject", subject),
+		slog.String("file", filePath))
+
+	return os.WriteFile(filePath, []byte(msg.String()), 0600)
+}
+
+// LogMailer writes messages to the server log instead of delivering them.
+type LogMailer struct{}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	slog.Info("Sending mail",
+		slog.String("to", to),
+		slog.String("subject", subject),
+		slog.String("body", body))
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	MessageTypeSay     = "say"
+	MessageTypeTell    = "tell"
+	MessageTypeChannel = "channel"
+	MessageTypeText    = "text"
+	MessageTypeCall    = "call"
+
+	// Messages are written to one file per day, files older than the retention period are removed
+	MessageLogFilePrefix = "messages-"
+	MessageLogFileSuffix = ".log"
+	MessageLogDateFormat = "2006-01-02"
+
+	MessageLogDefaultQueryLimit = 50
+	MessageLogDefaultReplaySize = 50
+)
+
+var (
+	MessageLogMgr = NewMessageLogManager()
+)
+
+type (
+	MessageLogEntry struct {
+		Time      time.Time `json:"time"`
+		Type      string    `json:"type"`
+		Sender    string    `json:"sender"`
+		SenderID  string    `json:"sender_id,omitempty"`
+		Recipient string    `json:"recipient,omitempty"`
+		Channel   string    `json:"channel,omitempty"`
+		RoomID    string    `json:"room_id,omitempty"`
+		Message   string    `json:"message"`
+	}
+
+	MessageLogQuery struct {
+		Character string
+		Since     time.Time
+		Limit     int
+	}
+
+	MessageLogManager struct {
+		sync.Mutex
+
+		currentDate string
+		replay      map[string][]*MessageLogEntry
+	}
+)
+
+func NewMessageLogManager() *MessageLogManager {
+	return &MessageLogManager{
+		replay: make(map[string][]*MessageLogEntry),
+	}
+}
+
+func (mgr *MessageLogManager) logPath() string {
+	return viper.GetString("data.message_log_path")
+}
+
+func messageLogFileName(t time.Time) string {
+	return MessageLogFilePrefix + t.Format(MessageLogDateFormat) + MessageLogFileSuffix
+}
+
+// Record writes the message to the log store and adds it to the replay buffer of each character it was
+// delivered to.
+func (mgr *MessageLogManager) Record(entry *MessageLogEntry, recipients []*Character) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	size := viper.GetInt("server.message_replay_size")
+	if size <= 0 {
+		size = MessageLogDefaultReplaySize
+	}
+	for _, c := range recipients {
+		name := strings.ToLower(c.Name)
+		mgr.replay[name] = append(mgr.replay[name], entry)
+		if len(mgr.replay[name]) > size {
+			mgr.replay[name] = mgr.replay[name][len(mgr.replay[name])-size:]
+		}
+	}
+
+	mgr.write(entry)
+}
+
+func (mgr *MessageLogManager) write(entry *MessageLogEntry) {
+	dataFilePath := mgr.logPath()
+	if dataFilePath == "" {
+		return
+	}
+
+	if err := os.MkdirAll(dataFilePath, 0755); err != nil {
+		slog.Error("failed to create message log directory",
+			slog.Any("error", err))
+		return
+	}
+
+	// Rotate to a new file when the day changes and clean up the old ones
+	date := entry.Time.Format(MessageLogDateFormat)
+	if date != mgr.currentDate {
+		mgr.currentDate = date
+		mgr.prune(entry.Time)
+	}
+
+	filePath := filepath.Join(dataFilePath, messageLogFileName(entry.Time))
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		slog.Error("failed to open message log",
+			slog.String("file", filePath),
+			slog.Any("error", err))
+		return
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		slog.Error("failed to write message log entry",
+			slog.Any("error", err))
+	}
+}
+
+// prune removes log files older than server.message_log_retention.
+func (mgr *MessageLogManager) prune(now time.Time) {
+	retention := viper.GetDuration("server.message_log_retention")
+	if retention <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(mgr.logPath())
+	if err != nil {
+		slog.Error("failed to read message log directory",
+			slog.Any("error", err))
+		return
+	}
+
+	cutoff := now.Add(-retention).Format(MessageLogDateFormat)
+	for _, e := range entries {
+		date, ok := messageLogFileDate(e.Name())
+		if !ok || date >= cutoff {
+			continue
+		}
+
+		slog.Info("Removing expired message log",
+			slog.String("file", e.Name()))
+
+		if err := os.Remove(filepath.Join(mgr.logPath(), e.Name())); err != nil {
+			slog.Error("failed to remove message log",
+				slog.String("file", e.Name()),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// messageLogFileDate returns the date portion of a message log file name.
+func messageLogFileDate(name string) (string, bool) {
+	if !strings.HasPrefix(name, MessageLogFilePrefix) || !strings.HasSuffix(name, MessageLogFileSuffix) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(name, MessageLogFilePrefix), MessageLogFileSuffix), true
+}
+
+// Replay returns up to count of the most recent messages delivered to the character, oldest first. If
+// msgType is set only messages of that type are returned.
+func (mgr *MessageLogManager) Replay(char *Character, count int, msgType string) []*MessageLogEntry {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	history := mgr.replay[strings.ToLower(char.Name)]
+
+	var entries []*MessageLogEntry
+	for i := len(history) - 1; i >= 0 && len(entries) < count; i-- {
+		if msgType != "" && history[i].Type != msgType {
+			continue
+		}
+		entries = append([]*MessageLogEntry{history[i]}, entries...)
+	}
+
+	return entries
+}
+
+// Query searches the log store for messages sent or received by a character, returning the most recent
+// matches oldest first.
+func (mgr *MessageLogManager) Query(q MessageLogQuery) []*MessageLogEntry {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	if q.Limit <= 0 {
+		q.Limit = MessageLogDefaultQueryLimit
+	}
+
+	files, err := os.ReadDir(mgr.logPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Error("failed to read message log directory",
+				slog.Any("error", err))
+		}
+		return nil
+	}
+
+	since := q.Since.Format(MessageLogDateFormat)
+
+	var entries []*MessageLogEntry
+	for _, f := range files {
+		date, ok := messageLogFileDate(f.Name())
+		if !ok || (!q.Since.IsZero() && date < since) {
+			continue
+		}
+
+		filePath := filepath.Join(mgr.logPath(), f.Name())
+		file, err := os.Open(filePath)
+		if err != nil {
+			slog.Error("failed to open message log",
+				slog.String("file", filePath),
+				slog.Any("error", err))
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry MessageLogEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				slog.Warn("Invalid message log entry",
+					slog.String("file", filePath),
+					slog.Any("error", err))
+				continue
+			}
+
+			if entry.Time.Before(q.Since) {
+				continue
+			}
+			if q.Character != "" && !strings.EqualFold(entry.Sender, q.Character) && !strings.EqualFold(entry.Recipient, q.Character) {
+				continue
+			}
+
+			entries = append(entries, &entry)
+		}
+		file.Close()
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+
+	if len(entries) > q.Limit {
+		entries = entries[len(entries)-q.Limit:]
+	}
+
+	return entries
+}
+
+// Format renders the entry for display in replay and chat log output.
+func (e *MessageLogEntry) Format() string {
+	switch e.Type {
+	case MessageTypeSay:
+		return fmt.Sprintf("{{%s says: \"%s\"}}::green", e.Sender, e.Message)
+	case MessageTypeTell:
+		return fmt.Sprintf("{{%s tells %s: \"%s\"}}::cyan", e.Sender, e.Recipient, e.Message)
+	case MessageTypeText:
+		return fmt.Sprintf("{{%s texts %s: \"%s\"}}::cyan", e.Sender, e.Recipient, e.Message)
+	case MessageTypeCall:
+		return fmt.Sprintf("{{%s calls %s: \"%s\"}}::cyan", e.Sender, e.Recipient, e.Message)
+	case MessageTypeChannel:
+		return fmt.Sprintf("{{[%s]}}::magenta|bold {{%s: %s}}::magenta", e.Channel, e.Sender, e.Message)
+	default:
+		return fmt.Sprintf("%s: %s", e.Sender, e.Message)
+	}
+}
+
+// ParseSince parses a point in time given either as a duration ago ("30m", "2h", "3d") or a date
+// ("2006-01-02").
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.ParseInLocation(MessageLogDateFormat, value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, use a duration such as 2h or 3d, or a date such as 2006-01-02", value)
+}
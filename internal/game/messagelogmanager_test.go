@@ -0,0 +1,72 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageLogManager(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("data.message_log_path", dir)
+	viper.Set("server.message_log_retention", 48*time.Hour)
+	viper.Set("server.message_replay_size", 2)
+	defer viper.Set("data.message_log_path", nil)
+	defer viper.Set("server.message_log_retention", nil)
+	defer viper.Set("server.message_replay_size", nil)
+
+	now := time.Now()
+	expired := filepath.Join(dir, messageLogFileName(now.AddDate(0, 0, -5)))
+	assert.NoError(t, os.WriteFile(expired, nil, 0600))
+
+	mgr := NewMessageLogManager()
+	alice := newTestCharacter("Alice")
+	bob := newTestCharacter("Bob")
+
+	mgr.Record(&MessageLogEntry{Time: now.Add(-2 * time.Hour), Type: MessageTypeSay, Sender: "Alice", Message: "hi"}, []*Character{bob})
+	mgr.Record(&MessageLogEntry{Time: now.Add(-time.Hour), Type: MessageTypeTell, Sender: "Alice", Recipient: "Bob", Message: "psst"}, []*Character{bob})
+	mgr.Record(&MessageLogEntry{Time: now, Type: MessageTypeSay, Sender: "Bob", Message: "hello"}, []*Character{alice})
+
+	assert.NoFileExists(t, expired, "files older than the retention period are pruned")
+
+	entries := mgr.Query(MessageLogQuery{Character: "bob"})
+	assert.Len(t, entries, 2, "matches both the sender and the recipient")
+	assert.Equal(t, "psst", entries[0].Message, "oldest entries come first")
+
+	entries = mgr.Query(MessageLogQuery{Character: "alice", Since: now.Add(-90 * time.Minute)})
+	assert.Len(t, entries, 1)
+
+	assert.Len(t, mgr.Replay(bob, 10, ""), 2)
+	assert.Len(t, mgr.Replay(bob, 10, MessageTypeTell), 1)
+	assert.Len(t, mgr.Replay(bob, 1, ""), 1)
+	assert.Empty(t, mgr.Replay(newTestCharacter("Carol"), 10, ""))
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+		err      bool
+	}{
+		{"2h", now.Add(-2 * time.Hour), false},
+		{"3d", now.AddDate(0, 0, -3), false},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if tt.err {
+			assert.Error(t, err, tt.value)
+			continue
+		}
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, got, tt.value)
+	}
+}
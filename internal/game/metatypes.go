@@ -1,6 +1,7 @@
 package game
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/i582/cfmt/cmd/cfmt"
@@ -27,8 +28,15 @@ const (
 	MetatypeNameDwarf MetatypeName = "Dwarf"
 	MetatypeNameOrk   MetatypeName = "Ork"
 	MetatypeNameTroll MetatypeName = "Troll"
+
+	MetatypeSizeSmall  = "small"
+	MetatypeSizeMedium = "medium" // Default
+	MetatypeSizeLarge  = "large"
 )
 
+// MetatypeSizes are the metatype sizes from smallest to largest.
+var MetatypeSizes = []string{MetatypeSizeSmall, MetatypeSizeMedium, MetatypeSizeLarge}
+
 type (
 	MetatypeName     string
 	RacialTrait      string
@@ -40,6 +48,7 @@ type (
 		Name        string `yaml:"name"`
 		Category    string `yaml:"category"`
 		Description string `yaml:"description"`
+		Size        string `yaml:"size,omitempty"` // One of MetatypeSizes, medium if not set
 
 		Body      Attribute[int]     `yaml:"body"`
 		Agility   Attribute[int]     `yaml:"agility"`
@@ -83,3 +92,17 @@ func (m *Metatype) GetSelectionInfo() string {
 	}
 	return output.String()
 }
+
+// SizeRank orders the metatype by size, smallest first.
+func (m *Metatype) SizeRank() int {
+	return MetatypeSizeRank(m.Size)
+}
+
+// MetatypeSizeRank orders a size among the MetatypeSizes, with unknown sizes counting as medium.
+func MetatypeSizeRank(size string) int {
+	if i := slices.Index(MetatypeSizes, strings.ToLower(size)); i >= 0 {
+		return i
+	}
+
+	return slices.Index(MetatypeSizes, MetatypeSizeMedium)
+}
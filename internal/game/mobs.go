@@ -29,6 +29,9 @@ type (
 		GameEntityInformation `yaml:",inline"`
 		GameEntityStats       `yaml:",inline"`
 		Spawns                []MobSpawns `yaml:"spawns"`
+		Patrol                []string    `yaml:"patrol,omitempty"` // Rooms the mob walks between in turn
+		AreaID                string      `yaml:"-"`
+		File                  string      `yaml:"-"`
 	}
 	MobInstance struct {
 		sync.RWMutex `yaml:"-"`
@@ -41,10 +44,17 @@ type (
 		Blueprint   *MobBlueprint `yaml:"-"`
 		RoomID      string        `yaml:"room_id"`
 		Room        *Room         `yaml:"-"`
+		Destination *Room         `yaml:"-"` // Room the mob is walking to, a step every tick
+		patrolStop  int           // Index of the next room in the blueprint's patrol
 	}
 	// TODO: Implement mob AI behaviors.
 )
 
+// QualifiedID returns the blueprint's area-qualified ID.
+func (bp *MobBlueprint) QualifiedID() string {
+	return QualifyID(bp.AreaID, bp.ID)
+}
+
 func (m *MobInstance) GetArmorValue() int {
 	var totalValue int
 
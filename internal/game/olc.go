@@ -0,0 +1,795 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/i582/cfmt/cmd/cfmt"
+)
+
+const (
+	olcDone      = "done"
+	olcValueMax  = 50
+	olcListNone  = "none"
+	olcExitAdd   = "add"
+	olcExitDel   = "remove"
+	olcExitDoor  = "door"
+	olcExitEdit  = "edit"
+	olcSpawnAdd  = "add"
+	olcSpawnDel  = "remove"
+	olcSpawnItem = "item"
+	olcSpawnMob  = "mob"
+)
+
+type (
+	// OLCField is a single editable field in an online creation editor.
+	OLCField struct {
+		Name  string
+		Value func() string
+		Edit  func(s ssh.Session) error
+	}
+)
+
+// RunOLCEditor shows the fields and their current values as a menu, editing the chosen field until the
+// builder is done. Changes are made in place so they apply to the live world straight away.
+func RunOLCEditor(s ssh.Session, title string, fields []OLCField) error {
+	for {
+		options := make([]MenuOption, 0, len(fields)+1)
+		for _, f := range fields {
+			options = append(options, MenuOption{
+				DisplayText: fmt.Sprintf("%-16s %s", f.Name+":", olcTruncate(f.Value())),
+				Value:       strings.ToLower(f.Name),
+				Description: f.Value(),
+			})
+		}
+		options = append(options, MenuOption{
+			DisplayText: "Done",
+			Value:       olcDone,
+			Description: "Finish editing",
+		})
+
+		choice, err := PromptForMenu(s, title, options)
+		if err != nil {
+			return err
+		}
+
+		if choice == olcDone {
+			return nil
+		}
+
+		for _, f := range fields {
+			if strings.ToLower(f.Name) != choice {
+				continue
+			}
+			if err := f.Edit(s); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// olcTruncate shortens a value to fit on a menu line.
+func olcTruncate(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len(value) > olcValueMax {
+		return value[:olcValueMax-3] + "..."
+	}
+
+	return value
+}
+
+// olcString is a single line text field, left unchanged when nothing is entered.
+func olcString(name string, value *string) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return *value },
+		Edit: func(s ssh.Session) error {
+			input, err := InputPrompt(s, cfmt.Sprintf("{{%s [%s]:}}::white|bold ", name, *value))
+			if err != nil {
+				return err
+			}
+			if input != "" {
+				*value = input
+			}
+
+			return nil
+		},
+	}
+}
+
+// olcText is a multi-line text field edited with the line editor.
+func olcText(name string, value *string) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return *value },
+		Edit: func(s ssh.Session) error {
+			WriteStringF(s, "{{Current %s:}}::cyan"+CRLF+"%s"+CRLF, strings.ToLower(name), *value)
+			text, ok, err := EditorPrompt(s, name)
+			if err != nil {
+				return err
+			}
+			if ok && text != "" {
+				*value = text
+			}
+
+			return nil
+		},
+	}
+}
+
+// olcInt is a whole number field.
+func olcInt(name string, value *int) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return strconv.Itoa(*value) },
+		Edit: func(s ssh.Session) error {
+			input, err := InputPrompt(s, cfmt.Sprintf("{{%s [%d]:}}::white|bold ", name, *value))
+			if err != nil {
+				return err
+			}
+			if input == "" {
+				return nil
+			}
+
+			n, err := strconv.Atoi(input)
+			if err != nil {
+				WriteStringF(s, "{{%q is not a whole number.}}::red"+CRLF, input)
+				return nil
+			}
+			*value = n
+
+			return nil
+		},
+	}
+}
+
+// olcFloat is a decimal number field.
+func olcFloat(name string, value *float64) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return strconv.FormatFloat(*value, 'f', -1, 64) },
+		Edit: func(s ssh.Session) error {
+			input, err := InputPrompt(s, cfmt.Sprintf("{{%s [%g]:}}::white|bold ", name, *value))
+			if err != nil {
+				return err
+			}
+			if input == "" {
+				return nil
+			}
+
+			f, err := strconv.ParseFloat(input, 64)
+			if err != nil {
+				WriteStringF(s, "{{%q is not a number.}}::red"+CRLF, input)
+				return nil
+			}
+			*value = f
+
+			return nil
+		},
+	}
+}
+
+// olcDuration is a length of time field such as "30m", cleared by entering "0".
+func olcDuration(name string, value *time.Duration) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return value.String() },
+		Edit: func(s ssh.Session) error {
+			input, err := InputPrompt(s, cfmt.Sprintf("{{%s [%s]:}}::white|bold ", name, *value))
+			if err != nil {
+				return err
+			}
+			if input == "" {
+				return nil
+			}
+
+			d, err := time.ParseDuration(input)
+			if err != nil || d < 0 {
+				WriteStringF(s, "{{%q is not a length of time such as 30m.}}::red"+CRLF, input)
+				return nil
+			}
+			*value = d
+
+			return nil
+		},
+	}
+}
+
+// olcBool is a yes/no field that toggles when chosen.
+func olcBool(name string, value *bool) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return strconv.FormatBool(*value) },
+		Edit: func(s ssh.Session) error {
+			*value = !*value
+			return nil
+		},
+	}
+}
+
+// olcList is a comma separated list field, cleared by entering "none". When valid is set only those values
+// are accepted.
+func olcList(name string, value *[]string, valid []string) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return strings.Join(*value, ", ") },
+		Edit: func(s ssh.Session) error {
+			if len(valid) > 0 {
+				WriteStringF(s, "{{Valid values:}}::cyan %s"+CRLF, strings.Join(valid, ", "))
+			}
+			input, err := InputPrompt(s, cfmt.Sprintf("{{%s, comma separated or 'none' [%s]:}}::white|bold ", name, strings.Join(*value, ", ")))
+			if err != nil {
+				return err
+			}
+			if input == "" {
+				return nil
+			}
+			if strings.EqualFold(input, olcListNone) {
+				*value = nil
+				return nil
+			}
+
+			var list []string
+			for _, v := range strings.Split(input, ",") {
+				v = strings.TrimSpace(v)
+				if v == "" {
+					continue
+				}
+				if len(valid) > 0 && !slices.Contains(valid, v) {
+					WriteStringF(s, "{{%q is not a valid value.}}::red"+CRLF, v)
+					return nil
+				}
+				list = append(list, v)
+			}
+			*value = list
+
+			return nil
+		},
+	}
+}
+
+// olcLines is a list field edited with the line editor, one value per line.
+func olcLines(name string, value *[]string) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return fmt.Sprintf("%d lines", len(*value)) },
+		Edit: func(s ssh.Session) error {
+			WriteStringF(s, "{{Current %s:}}::cyan"+CRLF+"%s"+CRLF, strings.ToLower(name), strings.Join(*value, CRLF))
+			text, ok, err := EditorPrompt(s, name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+
+			var lines []string
+			for _, line := range strings.Split(text, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					lines = append(lines, line)
+				}
+			}
+			*value = lines
+
+			return nil
+		},
+	}
+}
+
+// olcChoice is a field limited to a fixed set of values, picked from a menu.
+func olcChoice(name string, value *string, choices []string) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: func() string { return *value },
+		Edit: func(s ssh.Session) error {
+			options := make([]MenuOption, 0, len(choices))
+			for _, c := range choices {
+				options = append(options, MenuOption{DisplayText: c, Value: c, Description: c})
+			}
+
+			choice, err := PromptForMenu(s, "Select "+strings.ToLower(name), options)
+			if err != nil {
+				return err
+			}
+			*value = choice
+
+			return nil
+		},
+	}
+}
+
+// olcSubmenu is a field that opens its own editor.
+func olcSubmenu(name string, value func() string, edit func(s ssh.Session) error) OLCField {
+	return OLCField{
+		Name:  name,
+		Value: value,
+		Edit:  edit,
+	}
+}
+
+// EditRoom runs the room editor.
+func EditRoom(s ssh.Session, room *Room) error {
+	bias := string(room.Bias)
+
+	err := RunOLCEditor(s, fmt.Sprintf("Room Editor: %s", room.ID), []OLCField{
+		olcString("Title", &room.Title),
+		olcText("Description", &room.Description),
+		olcString("Landmark", &room.Landmark),
+		olcChoice("Light", &room.Light, LightLevels),
+		olcList("Tags", &room.Tags, nil),
+		olcChoice("Bias", &bias, []string{string(BiasNone), string(BiasGood)}),
+		olcSubmenu("Exits", func() string { return strings.Join(roomExitDirections(room), ", ") }, func(s ssh.Session) error {
+			return editRoomExits(s, room)
+		}),
+		olcSubmenu("Spawns", func() string { return strconv.Itoa(len(room.Spawns)) }, func(s ssh.Session) error {
+			return editRoomSpawns(s, room)
+		}),
+	})
+	room.Bias = Bias(bias)
+
+	return err
+}
+
+// roomExitDirections returns the directions of the room's exits in a stable order.
+func roomExitDirections(room *Room) []string {
+	room.RLock()
+	defer room.RUnlock()
+
+	dirs := make([]string, 0, len(room.Exits))
+	for dir := range room.Exits {
+		dirs = append(dirs, dir)
+	}
+	slices.Sort(dirs)
+
+	return dirs
+}
+
+func editRoomExits(s ssh.Session, room *Room) error {
+	for {
+		WriteString(s, CRLF+cfmt.Sprintf("{{Exits:}}::white|bold")+CRLF)
+		for _, dir := range roomExitDirections(room) {
+			exit := room.Exits[dir]
+			door := ""
+			if exit.Door != nil {
+				door = cfmt.Sprintf(" {{[door]}}::yellow")
+			}
+			if exit.MinRole != "" {
+				door += cfmt.Sprintf(" {{[%s]}}::magenta", exit.MinRole)
+			}
+			if exit.Hidden {
+				door += cfmt.Sprintf(" {{[hidden]}}::magenta")
+			}
+			if exit.OneWay {
+				door += cfmt.Sprintf(" {{[one-way]}}::magenta")
+			}
+			WriteStringF(s, "  {{%-6s}}::cyan %s%s"+CRLF, dir, exit.RoomID, door)
+		}
+
+		choice, err := PromptForMenu(s, "Edit Exits", []MenuOption{
+			{DisplayText: "Add or change an exit", Value: olcExitAdd, Description: "Link a direction to a room"},
+			{DisplayText: "Remove an exit", Value: olcExitDel, Description: "Remove the exit in a direction"},
+			{DisplayText: "Edit a door", Value: olcExitDoor, Description: "Add, edit or remove the door on an exit"},
+			{DisplayText: "Edit an exit", Value: olcExitEdit, Description: "Change an exit's type, visibility and requirements"},
+			{DisplayText: "Done", Value: olcDone, Description: "Finish editing exits"},
+		})
+		if err != nil {
+			return err
+		}
+
+		switch choice {
+		case olcDone:
+			return nil
+		case olcExitAdd:
+			if err := addRoomExit(s, room); err != nil {
+				return err
+			}
+		case olcExitDel:
+			dir, err := promptExitDirection(s, room)
+			if err != nil {
+				return err
+			}
+			if dir == "" {
+				continue
+			}
+			room.Lock()
+			delete(room.Exits, dir)
+			room.Unlock()
+			WriteStringF(s, "{{Removed the exit %s.}}::green"+CRLF, dir)
+		case olcExitDoor:
+			if err := editRoomDoor(s, room); err != nil {
+				return err
+			}
+		case olcExitEdit:
+			if err := editRoomExit(s, room); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// promptExitDirection asks for the direction of one of the room's exits, returning an empty direction if
+// there isn't one.
+func promptExitDirection(s ssh.Session, room *Room) (string, error) {
+	input, err := InputPrompt(s, cfmt.Sprintf("{{Direction:}}::white|bold "))
+	if err != nil {
+		return "", err
+	}
+
+	dir := ParseDirection(strings.ToLower(input))
+	if !room.HasExit(dir) {
+		WriteString(s, "{{There is no exit in that direction.}}::red"+CRLF)
+		return "", nil
+	}
+
+	return dir, nil
+}
+
+func addRoomExit(s ssh.Session, room *Room) error {
+	input, err := InputPrompt(s, cfmt.Sprintf("{{Direction:}}::white|bold "))
+	if err != nil {
+		return err
+	}
+	dir := ParseDirection(strings.ToLower(input))
+	if dir == "" {
+		WriteStringF(s, "{{%q is not a direction.}}::red"+CRLF, input)
+		return nil
+	}
+
+	input, err = InputPrompt(s, cfmt.Sprintf("{{Room ID:}}::white|bold "))
+	if err != nil {
+		return err
+	}
+	target := EntityMgr.ResolveRoom(room.AreaID, input)
+	if target == nil {
+		WriteStringF(s, "{{There is no room %q.}}::red"+CRLF, input)
+		return nil
+	}
+
+	room.Lock()
+	if room.Exits == nil {
+		room.Exits = make(map[string]*Exit)
+	}
+	exit, ok := room.Exits[dir]
+	if !ok {
+		exit = &Exit{Direction: dir}
+		room.Exits[dir] = exit
+	}
+	exit.RoomID = target.ID
+	if !strings.EqualFold(target.AreaID, room.AreaID) {
+		exit.RoomID = target.QualifiedID()
+	}
+	exit.Room = target
+	room.Unlock()
+
+	WriteStringF(s, "{{%s now leads to %s.}}::green"+CRLF, Capitalize(dir), target.ID)
+
+	// Offer to link the other way if the target room doesn't already have an exit back
+	reverse := ReverseDirection(dir)
+	if target.HasExit(reverse) {
+		return nil
+	}
+
+	input, err = InputPrompt(s, cfmt.Sprintf("{{Add an exit %s from %s back to this room? (y/N):}}::white|bold ", reverse, target.ID))
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(input, "y") && !strings.EqualFold(input, "yes") {
+		return nil
+	}
+
+	target.Lock()
+	if target.Exits == nil {
+		target.Exits = make(map[string]*Exit)
+	}
+	back := &Exit{RoomID: room.ID, Room: room, Direction: reverse, Type: exit.Type, Door: exit.Door}
+	if !strings.EqualFold(target.AreaID, room.AreaID) {
+		back.RoomID = room.QualifiedID()
+	}
+	target.Exits[reverse] = back
+	target.Unlock()
+
+	WriteStringF(s, "{{%s from %s now leads here.}}::green"+CRLF, Capitalize(reverse), target.ID)
+
+	return nil
+}
+
+// editRoomDoor adds, edits or removes the door on an exit. A door is shared with the exit leading back,
+// unless the exit is one-way, so both sides open and close together.
+func editRoomDoor(s ssh.Session, room *Room) error {
+	dir, err := promptExitDirection(s, room)
+	if err != nil || dir == "" {
+		return err
+	}
+
+	exit := room.Exits[dir]
+	var reverse *Exit
+	if exit.Room != nil && !exit.OneWay {
+		reverse = exit.Room.Exits[ReverseDirection(dir)]
+	}
+
+	if exit.Door != nil {
+		choice, err := PromptForMenu(s, "Door", []MenuOption{
+			{DisplayText: "Edit the door", Value: "edit", Description: "Change the door's state, keys and lock"},
+			{DisplayText: "Remove the door", Value: olcExitDel, Description: "Remove the door from both sides"},
+		})
+		if err != nil {
+			return err
+		}
+
+		if choice == olcExitDel {
+			exit.Door = nil
+			if reverse != nil {
+				reverse.Door = nil
+			}
+			WriteString(s, "{{Door removed.}}::green"+CRLF)
+			return nil
+		}
+	} else {
+		exit.Door = &Door{}
+		if reverse != nil {
+			reverse.Door = exit.Door
+		}
+		WriteString(s, "{{Door added.}}::green"+CRLF)
+	}
+
+	door := exit.Door
+	authored := door.Authored()
+	closed, locked := authored.IsClosed, authored.IsLocked
+	err = RunOLCEditor(s, fmt.Sprintf("Door Editor: %s", dir), []OLCField{
+		olcBool("Closed", &closed),
+		olcBool("Locked", &locked),
+		olcList("Keys", &door.KeyIDs, nil),
+		olcInt("Pick Difficulty", &door.PickDifficulty),
+	})
+	door.SetAuthoredState(closed, locked)
+
+	return err
+}
+
+// editRoomExit edits an exit's type, whether it's hidden or one-way, and the requirements to use it.
+func editRoomExit(s ssh.Session, room *Room) error {
+	dir, err := promptExitDirection(s, room)
+	if err != nil || dir == "" {
+		return err
+	}
+
+	exit := room.Exits[dir]
+	maxSize := exit.MaxSize
+	if maxSize == "" {
+		maxSize = olcListNone
+	}
+
+	err = RunOLCEditor(s, fmt.Sprintf("Exit Editor: %s", dir), []OLCField{
+		olcChoice("Type", &exit.Type, ExitTypes),
+		olcBool("Hidden", &exit.Hidden),
+		olcInt("Search difficulty", &exit.SearchDifficulty),
+		olcBool("One-way", &exit.OneWay),
+		olcSubmenu("Min role", func() string { return exit.MinRole }, func(s ssh.Session) error {
+			return editExitRole(s, exit)
+		}),
+		olcInt("Climb difficulty", &exit.ClimbDifficulty),
+		olcString("Required tag", &exit.RequiredTag),
+		olcChoice("Max size", &maxSize, append([]string{olcListNone}, MetatypeSizes...)),
+		olcString("Fail message", &exit.FailMessage),
+	})
+
+	exit.MaxSize = maxSize
+	if maxSize == olcListNone {
+		exit.MaxSize = ""
+	}
+
+	return err
+}
+
+// editExitRole limits an exit to characters in a permission group or a higher one.
+func editExitRole(s ssh.Session, exit *Exit) error {
+	groups := make([]*PermissionGroup, 0, len(PermissionMgr.GetGroups()))
+	for _, g := range PermissionMgr.GetGroups() {
+		groups = append(groups, g)
+	}
+	slices.SortFunc(groups, func(a, b *PermissionGroup) int { return a.Level - b.Level })
+
+	options := []MenuOption{{DisplayText: olcListNone, Value: olcListNone, Description: "Anyone can use the exit"}}
+	for _, g := range groups {
+		options = append(options, MenuOption{DisplayText: g.ID, Value: g.ID, Description: fmt.Sprintf("Level %d and above", g.Level)})
+	}
+
+	choice, err := PromptForMenu(s, "Select role", options)
+	if err != nil {
+		return err
+	}
+
+	exit.MinRole = choice
+	if choice == olcListNone {
+		exit.MinRole = ""
+	}
+
+	return nil
+}
+
+func editRoomSpawns(s ssh.Session, room *Room) error {
+	for {
+		WriteString(s, CRLF+cfmt.Sprintf("{{Spawns:}}::white|bold")+CRLF)
+		for i, spawn := range room.Spawns {
+			kind, id := olcSpawnItem, spawn.ItemID
+			if spawn.MobID != "" {
+				kind, id = olcSpawnMob, spawn.MobID
+			}
+			WriteStringF(s, "  {{%d}}::green. {{%-4s}}::cyan %s x%d (%d%%)"+CRLF, i+1, kind, id, max(spawn.Quantity, 1), spawnChance(spawn))
+		}
+
+		choice, err := PromptForMenu(s, "Edit Spawns", []MenuOption{
+			{DisplayText: "Add a spawn", Value: olcSpawnAdd, Description: "Spawn an item or mob when the room resets"},
+			{DisplayText: "Remove a spawn", Value: olcSpawnDel, Description: "Remove a spawn by number"},
+			{DisplayText: "Done", Value: olcDone, Description: "Finish editing spawns"},
+		})
+		if err != nil {
+			return err
+		}
+
+		switch choice {
+		case olcDone:
+			return nil
+		case olcSpawnAdd:
+			spawn, err := promptRoomSpawn(s, room.AreaID)
+			if err != nil {
+				return err
+			}
+			if spawn == nil {
+				continue
+			}
+			room.Lock()
+			room.Spawns = append(room.Spawns, *spawn)
+			room.Unlock()
+		case olcSpawnDel:
+			input, err := InputPrompt(s, cfmt.Sprintf("{{Spawn number:}}::white|bold "))
+			if err != nil {
+				return err
+			}
+			n, err := strconv.Atoi(input)
+			if err != nil || n < 1 || n > len(room.Spawns) {
+				WriteString(s, "{{There is no spawn with that number.}}::red"+CRLF)
+				continue
+			}
+			room.Lock()
+			room.Spawns = slices.Delete(room.Spawns, n-1, n)
+			room.Unlock()
+		}
+	}
+}
+
+// spawnChance returns the spawn's chance, which defaults to always.
+func spawnChance(spawn RoomSpawn) int {
+	if spawn.Chance == 0 {
+		return 100
+	}
+
+	return spawn.Chance
+}
+
+// promptRoomSpawn asks for the details of a new spawn, returning nil if the blueprint doesn't exist.
+func promptRoomSpawn(s ssh.Session, areaID string) (*RoomSpawn, error) {
+	kind, err := PromptForMenu(s, "Spawn Type", []MenuOption{
+		{DisplayText: "Item", Value: olcSpawnItem, Description: "Spawn an item into the room"},
+		{DisplayText: "Mob", Value: olcSpawnMob, Description: "Spawn a mob into the room"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := InputPrompt(s, cfmt.Sprintf("{{%s ID:}}::white|bold ", Capitalize(kind)))
+	if err != nil {
+		return nil, err
+	}
+
+	spawn := &RoomSpawn{}
+	switch kind {
+	case olcSpawnItem:
+		if EntityMgr.ResolveItemBlueprint(areaID, id) == nil {
+			WriteStringF(s, "{{There is no item %q.}}::red"+CRLF, id)
+			return nil, nil
+		}
+		spawn.ItemID = id
+	case olcSpawnMob:
+		if EntityMgr.ResolveMobBlueprint(areaID, id) == nil {
+			WriteStringF(s, "{{There is no mob %q.}}::red"+CRLF, id)
+			return nil, nil
+		}
+		spawn.MobID = id
+	}
+
+	if err := olcInt("Quantity", &spawn.Quantity).Edit(s); err != nil {
+		return nil, err
+	}
+	if err := olcInt("Chance", &spawn.Chance).Edit(s); err != nil {
+		return nil, err
+	}
+
+	return spawn, nil
+}
+
+// EditItemBlueprint runs the item editor.
+func EditItemBlueprint(s ssh.Session, bp *ItemBlueprint) error {
+	return RunOLCEditor(s, fmt.Sprintf("Item Editor: %s", bp.ID), []OLCField{
+		olcString("Name", &bp.Name),
+		olcText("Description", &bp.Description),
+		olcString("Type", &bp.Type),
+		olcString("Category", &bp.Category),
+		olcList("Tags", &bp.Tags, nil),
+		olcList("Equip Slots", &bp.EquipSlots, append([]string{EquipSlotNone}, EquipSlots...)),
+		olcFloat("Weight", &bp.Weight),
+		olcInt("Cost", &bp.Cost),
+		olcInt("Availability", &bp.Availability),
+		olcChoice("Legality", &bp.Legality, []string{LegalityTypeLegal, LegalityTypeRestricted, LegalityTypeForbidden}),
+		olcBool("Hide", &bp.Hide),
+	})
+}
+
+// EditMobBlueprint runs the mob editor.
+func EditMobBlueprint(s ssh.Session, bp *MobBlueprint) error {
+	metatypeID := bp.MetatypeID
+
+	err := RunOLCEditor(s, fmt.Sprintf("Mob Editor: %s", bp.ID), []OLCField{
+		olcString("Name", &bp.Name),
+		olcString("Title", &bp.Title),
+		olcText("Description", &bp.Description),
+		olcText("Long Description", &bp.LongDescription),
+		olcString("Metatype", &metatypeID),
+		olcChoice("Sex", &bp.Sex, []string{SexMale, SexFemale, SexNonBinary}),
+		olcChoice("Disposition", &bp.GeneralDisposition, []string{DispositionFriendly, DispositionNeutral, DispositionAggressive}),
+		olcInt("Professional Rating", &bp.ProfessionalRating),
+		olcList("Tags", &bp.Tags, nil),
+		olcSubmenu("Attributes", func() string {
+			return fmt.Sprintf("B%d A%d R%d S%d W%d L%d I%d C%d", bp.Body, bp.Agility, bp.Reaction, bp.Strength,
+				bp.Willpower, bp.Logic, bp.Intuition, bp.Charisma)
+		}, func(s ssh.Session) error {
+			return RunOLCEditor(s, fmt.Sprintf("Mob Attributes: %s", bp.ID), []OLCField{
+				olcInt("Body", &bp.Body),
+				olcInt("Agility", &bp.Agility),
+				olcInt("Reaction", &bp.Reaction),
+				olcInt("Strength", &bp.Strength),
+				olcInt("Willpower", &bp.Willpower),
+				olcInt("Logic", &bp.Logic),
+				olcInt("Intuition", &bp.Intuition),
+				olcInt("Charisma", &bp.Charisma),
+				olcFloat("Essence", &bp.Essence),
+				olcInt("Magic", &bp.Magic),
+				olcInt("Resonance", &bp.Resonance),
+			})
+		}),
+	})
+
+	// Keep the metatype pointer in step with the ID
+	if metatypeID != bp.MetatypeID {
+		if metatype := EntityMgr.GetMetatype(metatypeID); metatype != nil {
+			bp.MetatypeID = metatypeID
+			bp.Metatype = metatype
+		} else {
+			WriteStringF(s, "{{There is no metatype %q, the metatype was not changed.}}::red"+CRLF, metatypeID)
+		}
+	}
+
+	return err
+}
+
+// EditArea runs the area editor.
+func EditArea(s ssh.Session, area *Area) error {
+	return RunOLCEditor(s, fmt.Sprintf("Area Editor: %s", area.ID), []OLCField{
+		olcString("Title", &area.Title),
+		olcText("Description", &area.Description),
+		olcList("Builders", &area.Builders, nil),
+		olcInt("Min karma", &area.MinKarma),
+		olcInt("Max karma", &area.MaxKarma),
+		olcChoice("Threat level", &area.ThreatLevel, ThreatLevels),
+		olcDuration("Reset interval", &area.ResetInterval),
+		olcList("Flags", &area.Flags, AreaFlags),
+		olcList("Room tags", &area.RoomTags, nil),
+		olcLines("Ambient messages", &area.AmbientMessages),
+		olcDuration("Ambient interval", &area.AmbientInterval),
+		olcList("Weather", &area.Weather, Weathers),
+	})
+}
@@ -0,0 +1,228 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/i582/cfmt/cmd/cfmt"
+)
+
+const (
+	PathMaxDepth     = 100 // Rooms searched away from the start before giving up
+	SpeedwalkMaxStep = 50  // Steps a speedwalk can take
+)
+
+var (
+	ErrNoPath           = errors.New("there is no way to get there from here")
+	ErrInvalidSpeedwalk = errors.New("invalid speedwalk")
+
+	// pathDirections are the directions searched, in the order they are tried so paths are the same every
+	// time.
+	pathDirections = []string{"north", "east", "south", "west", "up", "down"}
+)
+
+type (
+	// PathOptions decides which exits a path can use.
+	PathOptions struct {
+		Character *Character // Whose role, keys and found exits are checked, nil for mobs which only use unrestricted exits
+		OpenDoors bool       // Closed doors that aren't locked, or that the character has a key for, can be used
+		MaxDepth  int        // Defaults to PathMaxDepth
+	}
+
+	// PathStep is one move along a path.
+	PathStep struct {
+		Direction string
+		Room      *Room
+	}
+)
+
+// CanPass reports whether the exit can be used by the path.
+func (o PathOptions) CanPass(from *Room, exit *Exit) bool {
+	if exit.Room == nil || !exit.VisibleTo(o.Character, from) || !exit.Allows(o.Character) {
+		return false
+	}
+
+	if exit.Door == nil || !exit.Door.IsClosed {
+		return true
+	}
+	if !o.OpenDoors {
+		return false
+	}
+	if !exit.Door.IsLocked {
+		return true
+	}
+
+	return o.Character != nil && o.Character.HasKeyFor(from, exit.Door)
+}
+
+// FindPath returns the steps of a shortest path between two rooms, nil if already there, or ErrNoPath if
+// the options don't leave a way through.
+func FindPath(from, to *Room, opts PathOptions) ([]PathStep, error) {
+	return FindPathTo(from, func(r *Room) bool { return r == to }, opts)
+}
+
+// FindPathTo returns the steps of a shortest path to the nearest room that matches.
+func FindPathTo(from *Room, match func(*Room) bool, opts PathOptions) ([]PathStep, error) {
+	if from == nil {
+		return nil, ErrNoPath
+	}
+	if match(from) {
+		return nil, nil
+	}
+
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = PathMaxDepth
+	}
+
+	type visit struct {
+		prev  *Room
+		step  PathStep
+		depth int
+	}
+	visited := map[*Room]visit{from: {}}
+	queue := []*Room{from}
+
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		depth := visited[room].depth
+		if depth >= maxDepth {
+			continue
+		}
+
+		for _, dir := range pathDirections {
+			exit, ok := room.Exits[dir]
+			if !ok || !opts.CanPass(room, exit) {
+				continue
+			}
+			if _, seen := visited[exit.Room]; seen {
+				continue
+			}
+
+			visited[exit.Room] = visit{prev: room, step: PathStep{Direction: dir, Room: exit.Room}, depth: depth + 1}
+			if !match(exit.Room) {
+				queue = append(queue, exit.Room)
+				continue
+			}
+
+			// Walk back to the start
+			steps := make([]PathStep, depth+1)
+			for r := exit.Room; r != from; r = visited[r].prev {
+				steps[visited[r].depth-1] = visited[r].step
+			}
+
+			return steps, nil
+		}
+	}
+
+	return nil, ErrNoPath
+}
+
+// FindLandmark returns a path to the nearest room whose landmark, title or tags match the name.
+func FindLandmark(from *Room, name string, opts PathOptions) ([]PathStep, error) {
+	return FindPathTo(from, func(r *Room) bool {
+		return strings.EqualFold(r.Landmark, name) ||
+			strings.EqualFold(r.Title, name) ||
+			HasAnyTag(r.AllTags(), []string{name})
+	}, opts)
+}
+
+// FormatSpeedwalk turns a path into a speedwalk such as "3n2eu".
+func FormatSpeedwalk(steps []PathStep) string {
+	var sb strings.Builder
+	for i := 0; i < len(steps); {
+		n := 1
+		for i+n < len(steps) && steps[i+n].Direction == steps[i].Direction {
+			n++
+		}
+		if n > 1 {
+			sb.WriteString(strconv.Itoa(n))
+		}
+		sb.WriteByte(steps[i].Direction[0])
+		i += n
+	}
+
+	return sb.String()
+}
+
+// ParseSpeedwalk turns a speedwalk such as "3n2e u" into the directions to walk.
+func ParseSpeedwalk(walk string) ([]string, error) {
+	var dirs []string
+	count := ""
+	for _, r := range strings.ToLower(walk) {
+		switch {
+		case unicode.IsSpace(r) || r == ',':
+			if count != "" {
+				return nil, fmt.Errorf("%w: %s has no direction", ErrInvalidSpeedwalk, count)
+			}
+		case unicode.IsDigit(r):
+			count += string(r)
+		default:
+			dir := ParseDirection(string(r))
+			if dir == "" {
+				return nil, fmt.Errorf("%w: %q is not a direction", ErrInvalidSpeedwalk, r)
+			}
+
+			n := 1
+			if count != "" {
+				var err error
+				if n, err = strconv.Atoi(count); err != nil || n <= 0 {
+					return nil, fmt.Errorf("%w: %s is not a number of steps", ErrInvalidSpeedwalk, count)
+				}
+				count = ""
+			}
+			// Check the count before adding the steps so a huge count can't eat memory
+			if n > SpeedwalkMaxStep-len(dirs) {
+				return nil, fmt.Errorf("%w: more than %d steps", ErrInvalidSpeedwalk, SpeedwalkMaxStep)
+			}
+			for range n {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+
+	if count != "" {
+		return nil, fmt.Errorf("%w: %s has no direction", ErrInvalidSpeedwalk, count)
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("%w: no directions", ErrInvalidSpeedwalk)
+	}
+
+	return dirs, nil
+}
+
+// PathTo finds a path for the mob to a room, for AI that wants to walk somewhere. Mobs can open doors but
+// not unlock them and can't use exits restricted to roles.
+func (m *MobInstance) PathTo(to *Room) ([]PathStep, error) {
+	return FindPath(m.Room, to, PathOptions{OpenDoors: true})
+}
+
+// StepToward moves the mob one room along the path to a room, reporting whether it moved.
+func (m *MobInstance) StepToward(to *Room) bool {
+	steps, err := m.PathTo(to)
+	if err != nil || len(steps) == 0 {
+		return false
+	}
+
+	m.MoveToRoom(steps[0].Direction, steps[0].Room)
+
+	return true
+}
+
+// MoveToRoom moves the mob through an exit, telling both rooms.
+func (m *MobInstance) MoveToRoom(dir string, next *Room) {
+	if exit, ok := m.Room.Exits[dir]; ok && exit.Door != nil && exit.Door.IsClosed {
+		exit.Door.IsClosed = false
+		m.Room.Broadcast(cfmt.Sprintf("{{%s opens the door to the %s.}}::green"+CRLF, Capitalize(m.Blueprint.Name), dir), nil)
+	}
+
+	prev := m.Room
+	prev.Broadcast(cfmt.Sprintf("{{%s leaves %s.}}::green"+CRLF, Capitalize(m.Blueprint.Name), dir), nil)
+	prev.RemoveMobInstance(m)
+	next.AddMobInstance(m)
+	next.Broadcast(cfmt.Sprintf("{{%s arrives.}}::green"+CRLF, Capitalize(m.Blueprint.Name)), nil)
+}
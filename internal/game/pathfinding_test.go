@@ -0,0 +1,123 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPath(t *testing.T) {
+	withTestPermissionGroups(t)
+
+	hall := &Room{ID: "hall", Title: "Hall"}
+	study := &Room{ID: "study", Title: "Study"}
+	library := &Room{ID: "library", Title: "Library", Landmark: "Archives"}
+	vault := &Room{ID: "vault", Title: "Vault"}
+	linkTestRooms(hall, "north", study)
+	linkTestRooms(study, "north", library)
+	linkTestRooms(library, "east", vault)
+
+	steps, err := FindPath(hall, vault, PathOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2ne", FormatSpeedwalk(steps))
+	assert.Equal(t, vault, steps[len(steps)-1].Room)
+
+	steps, err = FindLandmark(hall, "archives", PathOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2n", FormatSpeedwalk(steps))
+
+	steps, err = FindPath(hall, hall, PathOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
+
+	// Closed doors need OpenDoors and locked ones a key
+	door := &Door{IsClosed: true}
+	library.Exits["east"].Door = door
+	_, err = FindPath(hall, vault, PathOptions{})
+	assert.ErrorIs(t, err, ErrNoPath)
+	_, err = FindPath(hall, vault, PathOptions{OpenDoors: true})
+	assert.NoError(t, err)
+	door.IsLocked = true
+	_, err = FindPath(hall, vault, PathOptions{OpenDoors: true})
+	assert.ErrorIs(t, err, ErrNoPath)
+	door.IsClosed, door.IsLocked = false, false
+
+	// Exits restricted to a role are closed to lower roles and mobs
+	study.Exits["north"].MinRole = "moderator"
+	player := newTestCharacter("Bob")
+	player.Role = "player"
+	mod := newTestCharacter("Alice")
+	mod.Role = "moderator"
+	_, err = FindPath(hall, vault, PathOptions{Character: player})
+	assert.ErrorIs(t, err, ErrNoPath)
+	_, err = FindPath(hall, vault, PathOptions{Character: mod})
+	assert.NoError(t, err)
+	_, err = FindPath(hall, vault, PathOptions{})
+	assert.ErrorIs(t, err, ErrNoPath)
+}
+
+func TestParseSpeedwalk(t *testing.T) {
+	dirs, err := ParseSpeedwalk("3n2e u")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"north", "north", "north", "east", "east", "up"}, dirs)
+
+	for _, walk := range []string{"", "3", "2x", "3 n", "99n", "0n", "999999999n", "99999999999999999999n", "30n30e"} {
+		_, err := ParseSpeedwalk(walk)
+		assert.ErrorIs(t, err, ErrInvalidSpeedwalk, walk)
+	}
+
+	steps := []PathStep{{Direction: "north"}, {Direction: "north"}, {Direction: "east"}, {Direction: "down"}, {Direction: "down"}}
+	assert.Equal(t, "2ne2d", FormatSpeedwalk(steps))
+}
+
+func TestMobStepToward(t *testing.T) {
+	hall := &Room{ID: "hall", MobInstances: make(map[string]*MobInstance)}
+	study := &Room{ID: "study", MobInstances: make(map[string]*MobInstance)}
+	library := &Room{ID: "library", MobInstances: make(map[string]*MobInstance)}
+	linkTestRooms(hall, "north", study)
+	linkTestRooms(study, "east", library)
+	study.Exits["east"].Door = &Door{IsClosed: true}
+	library.Exits["west"].Door = study.Exits["east"].Door
+
+	mob := &MobInstance{InstanceID: "1", Blueprint: &MobBlueprint{}}
+	mob.Blueprint.Name = "a butler"
+	hall.AddMobInstance(mob)
+
+	assert.True(t, mob.StepToward(library))
+	assert.Equal(t, study, mob.Room)
+	assert.NotContains(t, hall.MobInstances, "1")
+	assert.True(t, mob.StepToward(library))
+	assert.Equal(t, library, mob.Room)
+	assert.False(t, study.Exits["east"].Door.IsClosed, "mobs open doors on the way")
+	assert.False(t, mob.StepToward(library))
+}
+
+func TestMobPatrol(t *testing.T) {
+	withTestEntityManager(t)
+	hall := &Room{ID: "hall", AreaID: "test", MobInstances: make(map[string]*MobInstance)}
+	study := &Room{ID: "study", AreaID: "test", MobInstances: make(map[string]*MobInstance)}
+	library := &Room{ID: "library", AreaID: "test", MobInstances: make(map[string]*MobInstance)}
+	linkTestRooms(hall, "north", study)
+	linkTestRooms(study, "east", library)
+	for _, r := range []*Room{hall, study, library} {
+		EntityMgr.AddRoom(r)
+	}
+
+	mob := &MobInstance{InstanceID: "1", Blueprint: &MobBlueprint{AreaID: "test", Patrol: []string{"hall", "library"}}}
+	mob.Blueprint.Name = "a guard"
+	hall.AddMobInstance(mob)
+	EntityMgr.AddMobInstance(mob)
+
+	EntityMgr.PulseMobs()
+	assert.Equal(t, study, mob.Room, "the mob skips the patrol room it's already in")
+	assert.Equal(t, library, mob.Destination)
+	EntityMgr.PulseMobs()
+	assert.Equal(t, library, mob.Room)
+
+	// Once it arrives it turns around
+	EntityMgr.PulseMobs()
+	assert.Nil(t, mob.Destination)
+	EntityMgr.PulseMobs()
+	assert.Equal(t, study, mob.Room)
+	assert.Equal(t, hall, mob.Destination)
+}
@@ -0,0 +1,143 @@
+package game
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	PermissionMgr = NewPermissionManager()
+)
+
+type PermissionManager struct {
+	sync.RWMutex
+
+	groups map[string]*PermissionGroup
+}
+
+func NewPermissionManager() *PermissionManager {
+	return &PermissionManager{
+		groups: make(map[string]*PermissionGroup),
+	}
+}
+
+func (mgr *PermissionManager) AddGroup(g *PermissionGroup) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	mgr.groups[strings.ToLower(g.ID)] = g
+}
+
+func (mgr *PermissionManager) GetGroup(id string) *PermissionGroup {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return mgr.groups[strings.ToLower(id)]
+}
+
+func (mgr *PermissionManager) GetGroups() map[string]*PermissionGroup {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return mgr.groups
+}
+
+// GetGroupPermissions returns the permissions of the group and every group it inherits from.
+func (mgr *PermissionManager) GetGroupPermissions(id string) []string {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	var permissions []string
+	mgr.collectPermissions(strings.ToLower(id), make(map[string]bool), &permissions)
+
+	return permissions
+}
+
+func (mgr *PermissionManager) collectPermissions(id string, visited map[string]bool, permissions *[]string) {
+	if visited[id] {
+		return
+	}
+	visited[id] = true
+
+	g, ok := mgr.groups[id]
+	if !ok {
+		return
+	}
+
+	*permissions = append(*permissions, g.Permissions...)
+	for _, parent := range g.Inherits {
+		mgr.collectPermissions(strings.ToLower(parent), visited, permissions)
+	}
+}
+
+// GroupHasPermission reports whether the group, directly or through inheritance, has the permission.
+func (mgr *PermissionManager) GroupHasPermission(id, permission string) bool {
+	return MatchAnyPermission(mgr.GetGroupPermissions(id), permission)
+}
+
+// GetGroupLevel returns the level of the group or 0 if the group is unknown.
+func (mgr *PermissionManager) GetGroupLevel(id string) int {
+	g := mgr.GetGroup(id)
+	if g == nil {
+		return 0
+	}
+
+	return g.Level
+}
+
+func (mgr *PermissionManager) LoadDataFiles() {
+	dataFilePath := viper.GetString("data.permissions_path")
+	if dataFilePath == "" {
+		dataFilePath = PermissionsFilepath
+	}
+
+	slog.Info("Loading permission groups",
+		slog.String("datafile_path", dataFilePath))
+
+	st := time.Now()
+	files, err := os.ReadDir(dataFilePath)
+	if err != nil {
+		slog.Error("failed reading directory",
+			slog.String("datafile_path", dataFilePath),
+			slog.Any("error", err))
+	}
+
+	for _, file := range files {
+		if !IsYAMLFile(file.Name()) {
+			continue
+		}
+
+		var g PermissionGroup
+		if err := LoadYAML(filepath.Join(dataFilePath, file.Name()), &g); err != nil {
+			slog.Error("failed to unmarshal permission group data",
+				slog.Any("error", err),
+				slog.String("file", file.Name()))
+			continue
+		}
+		mgr.AddGroup(&g)
+
+		slog.Debug("Loaded permission group",
+			slog.String("group_id", g.ID),
+			slog.Int("level", g.Level))
+	}
+
+	for _, g := range mgr.groups {
+		for _, parent := range g.Inherits {
+			if mgr.GetGroup(parent) == nil {
+				slog.Warn("Permission group inherits unknown group",
+					slog.String("group_id", g.ID),
+					slog.String("inherits", parent))
+			}
+		}
+	}
+
+	slog.Info("Loaded permission groups",
+		slog.Duration("took", time.Since(st)),
+		slog.Int("count", len(mgr.groups)))
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	PermissionsFilepath = "_data/permissions"
+
+	PermissionWildcard = "*"
+
+	PermissionWorldList     = "world.list"
+	PermissionWorldGoto     = "world.goto"
+	PermissionWorldMobStats = "world.mobstats"
+	PermissionWorldSpawn    = "world.spawn"
+	PermissionWorldRoomIDs  = "world.room_ids"
+	PermissionWorldEdit     = "world.edit"
+	PermissionWorldReload   = "world.reload"
+	PermissionWorldLint     = "world.lint"
+
+	PermissionAdminGrant       = "admin.grant"
+	PermissionAdminPermissions = "admin.permissions"
+	PermissionAdminAudit       = "admin.audit"
+	PermissionAdminChatlog     = "admin.chatlog"
+	PermissionAdminBoards      = "admin.boards"
+	PermissionBypassIgnore     = "admin.bypass_ignore"
+	PermissionAdminInvis       = "admin.invis"
+)
+
+// corePermissions are the permissions checked by the game itself rather than by commands or channels.
+var corePermissions = []string{
+	PermissionWorldList, PermissionWorldGoto, PermissionWorldMobStats, PermissionWorldSpawn, PermissionWorldRoomIDs,
+	PermissionWorldEdit, PermissionWorldReload, PermissionWorldLint, PermissionAdminGrant, PermissionAdminPermissions,
+	PermissionAdminAudit, PermissionAdminChatlog, PermissionAdminBoards, PermissionBypassIgnore, PermissionAdminInvis,
+}
+
+type (
+	PermissionGroup struct {
+		ID          string   `yaml:"id"`
+		Name        string   `yaml:"name"`
+		Description string   `yaml:"description"`
+		Level       int      `yaml:"level"`
+		Color       string   `yaml:"color"`
+		Inherits    []string `yaml:"inherits"`
+		Permissions []string `yaml:"permissions"`
+	}
+)
+
+// MatchPermission reports whether the granted permission covers the requested one. A granted permission
+// of "*" covers everything and "world.*" covers every permission starting with "world.".
+func MatchPermission(granted, requested string) bool {
+	granted = strings.ToLower(strings.TrimSpace(granted))
+	requested = strings.ToLower(strings.TrimSpace(requested))
+
+	switch {
+	case granted == PermissionWildcard:
+		return true
+	case granted == requested:
+		return true
+	case strings.HasSuffix(granted, ".*"):
+		return strings.HasPrefix(requested, strings.TrimSuffix(granted, "*"))
+	}
+
+	return false
+}
+
+// MatchAnyPermission reports whether any of the granted permissions covers the requested one.
+func MatchAnyPermission(granted []string, requested string) bool {
+	for _, g := range granted {
+		if MatchPermission(g, requested) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// KnownPermissions returns every permission the game checks, including those required by commands and
+// channels.
+func KnownPermissions() []string {
+	known := slices.Clone(corePermissions)
+	for _, cmd := range CommandMgr.GetCommands() {
+		if cmd.RequiredPermission != "" {
+			known = append(known, strings.ToLower(cmd.RequiredPermission))
+		}
+	}
+	for _, ch := range ChannelMgr.GetChannels() {
+		if ch.Permission != "" {
+			known = append(known, strings.ToLower(ch.Permission))
+		}
+	}
+	slices.Sort(known)
+
+	return slices.Compact(known)
+}
+
+// IsKnownPermission reports whether the permission is one the game checks. Wildcards are known if they
+// cover at least one known permission.
+func IsKnownPermission(permission string) bool {
+	permission = strings.ToLower(strings.TrimSpace(permission))
+	if permission == PermissionWildcard {
+		return true
+	}
+
+	for _, known := range KnownPermissions() {
+		if known == permission || (strings.HasSuffix(permission, ".*") && MatchPermission(permission, known)) {
+			return true
+		}
+	}
+
+	return false
+}
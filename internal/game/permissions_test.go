@@ -0,0 +1,83 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPermission(t *testing.T) {
+	tests := []struct {
+		granted   string
+		requested string
+		expected  bool
+	}{
+		{"*", "world.spawn", true},
+		{"world.spawn", "world.spawn", true},
+		{"World.Spawn", "world.spawn", true},
+		{"world.*", "world.spawn", true},
+		{"world.*", "admin.grant", false},
+		{"world.*", "worldly.spawn", false},
+		{"world.goto", "world.spawn", false},
+		{"", "world.spawn", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.granted+"/"+tt.requested, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchPermission(tt.granted, tt.requested))
+		})
+	}
+}
+
+func TestPermissionGroupInheritance(t *testing.T) {
+	mgr := NewPermissionManager()
+	mgr.AddGroup(&PermissionGroup{ID: "player"})
+	mgr.AddGroup(&PermissionGroup{ID: "helper", Inherits: []string{"player"}, Permissions: []string{"world.room_ids"}})
+	mgr.AddGroup(&PermissionGroup{ID: "builder", Inherits: []string{"helper"}, Permissions: []string{"world.*"}})
+	// Cyclic inheritance should not loop forever
+	mgr.AddGroup(&PermissionGroup{ID: "moderator", Inherits: []string{"helper", "moderator"}, Permissions: []string{"world.goto"}})
+
+	assert.False(t, mgr.GroupHasPermission("player", "world.room_ids"))
+	assert.True(t, mgr.GroupHasPermission("helper", "world.room_ids"))
+	assert.True(t, mgr.GroupHasPermission("builder", "world.spawn"))
+	assert.True(t, mgr.GroupHasPermission("moderator", "world.room_ids"))
+	assert.False(t, mgr.GroupHasPermission("moderator", "world.spawn"))
+	assert.False(t, mgr.GroupHasPermission("unknown", "world.spawn"))
+}
+
+func TestCharacterPermissions(t *testing.T) {
+	c := NewCharacter()
+
+	c.GrantPermission("World.Goto")
+	assert.True(t, c.HasPermission("world.goto"))
+
+	c.RevokePermission("world.goto")
+	assert.False(t, c.HasPermission("world.goto"))
+	assert.Empty(t, c.Permissions)
+	assert.Empty(t, c.Revoked, "removing a grant shouldn't add a revocation")
+
+	c.GrantPermission("world.*")
+	c.RevokePermission("world.spawn")
+	assert.True(t, c.HasPermission("world.goto"))
+	assert.False(t, c.HasPermission("world.spawn"), "revocations take precedence over grants")
+}
+
+func TestCheckPermissionChange(t *testing.T) {
+	withTestPermissionGroups(t)
+	PermissionMgr.GetGroup("moderator").Permissions = []string{PermissionAdminGrant, PermissionWorldGoto}
+	PermissionMgr.GetGroup("admin").Permissions = []string{PermissionWildcard}
+
+	admin := newTestCharacter("Alice")
+	admin.Role = "admin"
+	mod := newTestCharacter("Bob")
+	mod.Role = "moderator"
+	player := newTestCharacter("Carol")
+	player.Role = "player"
+
+	assert.Empty(t, checkPermissionChange(mod, player, PermissionWorldGoto))
+	assert.Contains(t, checkPermissionChange(mod, player, "world.flying"), "not a known permission")
+	assert.Contains(t, checkPermissionChange(mod, player, PermissionWildcard), "don't have")
+	assert.Contains(t, checkPermissionChange(mod, mod, PermissionWorldGoto), "below your level", "characters can't grant themselves")
+	assert.Contains(t, checkPermissionChange(mod, admin, PermissionWorldGoto), "below your level")
+	assert.Empty(t, checkPermissionChange(admin, mod, "world.*"))
+}
@@ -7,7 +7,7 @@ import (
 )
 
 const (
-	DefaultPrompt = "{{time}} {{date}} {{>}}::white|bold "
+	DefaultPrompt = "{{condition}}{{time}} {{date}} {{weather}}{{>}}::white|bold "
 )
 
 type ()
@@ -16,8 +16,10 @@ type ()
 // TODO: need a DoPrompt function that will handle the actual printing of the prompt
 var (
 	promptPlaceholders = map[string]func(*Character) string{
-		"{{time}}": GetFormattedGameTime,
-		"{{date}}": GetFormattedGameDate,
+		"{{time}}":      GetFormattedGameTime,
+		"{{date}}":      GetFormattedGameDate,
+		"{{weather}}":   GetFormattedWeather,
+		"{{condition}}": GetFormattedCondition,
 	}
 )
 
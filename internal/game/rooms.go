@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/i582/cfmt/cmd/cfmt"
 	"github.com/muesli/reflow/wordwrap"
 	ee "github.com/vansante/go-event-emitter"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -17,6 +19,7 @@ const (
 
 	RoomTagPeaceful = "Peaceful"
 	RoomTagElevator = "Elevator"
+	RoomTagOutdoors = "Outdoors" // Lit by the day and night
 
 	// Corporate & High-Security
 	RoomTagCorporate  = "Corporate"
@@ -72,6 +75,9 @@ const (
 	ExitTypePassage   = "passage" // Default
 )
 
+// ExitTypes are the types an exit can be.
+var ExitTypes = []string{ExitTypePassage, ExitTypeStairs, ExitTypeEscalator, ExitTypeLadder, ExitTypeRope, ExitTypeRamp, ExitTypeSlide, ExitTypeJumpPad}
+
 // TODO: do we want to persist the room state between resets (mobs, items, etc)?
 
 type (
@@ -82,14 +88,29 @@ type (
 		Room      *Room  `yaml:"-"`
 		RoomID    string `yaml:"room_id"`
 		Direction string `yaml:"direction"`
-		Door      *Door  `yaml:"door"`
+		Door      *Door  `yaml:"door,omitempty"`
 		Type      string `yaml:"type,omitempty"`
+
+		Hidden           bool `yaml:"hidden,omitempty"`            // Not shown or usable until found
+		SearchDifficulty int  `yaml:"search_difficulty,omitempty"` // Perception hits needed to find a hidden exit
+		OneWay           bool `yaml:"one_way,omitempty"`           // There's deliberately no way back
+
+		// Requirements to use the exit
+		MinRole         string `yaml:"min_role,omitempty"`         // Permission group needed to use the exit
+		ClimbDifficulty int    `yaml:"climb_difficulty,omitempty"` // Climbing hits needed, defaults by exit type
+		RequiredTag     string `yaml:"required_tag,omitempty"`     // Tag of an item that has to be carried, such as a keycard
+		MaxSize         string `yaml:"max_size,omitempty"`         // Largest metatype size that fits through
+		FailMessage     string `yaml:"fail_message,omitempty"`     // Shown instead of the default when a requirement isn't met
 	}
 	Door struct {
 		IsClosed       bool     `yaml:"is_closed"`
 		IsLocked       bool     `yaml:"is_locked"`
 		KeyIDs         []string `yaml:"key_ids"`
 		PickDifficulty int      `yaml:"pick_difficulty"`
+
+		// The state the door was built with, which is what gets saved, as players open and close it
+		authoredClosed bool
+		authoredLocked bool
 	}
 	Corrdinates struct {
 		X int `yaml:"x"`
@@ -97,10 +118,10 @@ type (
 		Z int `yaml:"z"`
 	}
 	RoomSpawn struct {
-		ItemID   string `yaml:"item_id"`
-		MobID    string `yaml:"mob_id"`
-		Chance   int    `yaml:"chance"`
-		Quantity int    `yaml:"quantity"`
+		ItemID   string `yaml:"item_id,omitempty"`
+		MobID    string `yaml:"mob_id,omitempty"`
+		Chance   int    `yaml:"chance,omitempty"`
+		Quantity int    `yaml:"quantity,omitempty"`
 	}
 	// TODO: Add Doors and Locks
 	// TODO: Keep track of items in the room between resets
@@ -117,6 +138,8 @@ type (
 		Area         *Area                   `yaml:"-"`
 		Title        string                  `yaml:"title"`
 		Description  string                  `yaml:"description"`
+		Landmark     string                  `yaml:"landmark,omitempty"` // Name the room can be found by with path
+		Light        string                  `yaml:"light,omitempty"`    // One of LightLevels, full if not set
 		Tags         []string                `yaml:"tags"`
 		Bias         Bias                    `yaml:"bias"`
 		Exits        map[string]*Exit        `yaml:"exits"`
@@ -125,6 +148,7 @@ type (
 		Characters   map[string]*Character   `yaml:"-"`
 		MobInstances map[string]*MobInstance `yaml:"-"`
 		Spawns       []RoomSpawn             `yaml:"spawns,omitempty"`
+		File         string                  `yaml:"-"` // File the room was loaded from
 		// SpawnedMobs         []*MonIN                  `yaml:"-"` // Mobs that have been spawned into the room
 		SpawnedMobInstances []*MobInstance `yaml:"-"` // Mob instances that have been spawned into the room
 	}
@@ -258,11 +282,111 @@ func (r *Room) RemoveCharacter(c *Character) {
 	delete(r.Characters, c.ID)
 }
 
+// HasCharacters reports whether any characters are in the room.
+func (r *Room) HasCharacters() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return len(r.Characters) > 0
+}
+
+// countItems counts the instances of the item blueprint lying in the room.
+func (r *Room) countItems(bp *ItemBlueprint) int {
+	r.RLock()
+	defer r.RUnlock()
+
+	count := 0
+	for _, i := range r.Inventory.Items {
+		if i.Blueprint == bp || strings.EqualFold(i.BlueprintID, bp.QualifiedID()) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// countMobs counts the instances of the mob blueprint in the room.
+func (r *Room) countMobs(bp *MobBlueprint) int {
+	r.RLock()
+	defer r.RUnlock()
+
+	count := 0
+	for _, m := range r.MobInstances {
+		if m.Blueprint == bp || strings.EqualFold(m.BlueprintID, bp.QualifiedID()) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AllTags returns the room's tags along with the default room tags of its area.
+func (r *Room) AllTags() []string {
+	if r.Area == nil {
+		return r.Tags
+	}
+
+	r.Area.RLock()
+	defer r.Area.RUnlock()
+
+	tags := slices.Clone(r.Tags)
+	for _, tag := range r.Area.RoomTags {
+		if !slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// HasFlag reports whether the room's area has the flag.
+func (r *Room) HasFlag(flag string) bool {
+	return r.Area != nil && r.Area.HasFlag(flag)
+}
+
+// AcceptsKey reports whether the item opens the door. Key IDs are resolved relative to the area the door
+// is in, so bare IDs mean that area's keys.
+func (d *Door) AcceptsKey(areaID string, bp *ItemBlueprint) bool {
+	for _, key := range d.KeyIDs {
+		if EntityMgr.ResolveItemBlueprint(areaID, key) == bp {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UnmarshalYAML loads the door and remembers the state it was built with.
+func (d *Door) UnmarshalYAML(value *yaml.Node) error {
+	type door Door
+	if err := value.Decode((*door)(d)); err != nil {
+		return err
+	}
+	d.authoredClosed, d.authoredLocked = d.IsClosed, d.IsLocked
+
+	return nil
+}
+
+// SetAuthoredState changes the state the door is built with, and its current state to match.
+func (d *Door) SetAuthoredState(closed, locked bool) {
+	d.IsClosed, d.IsLocked = closed, locked
+	d.authoredClosed, d.authoredLocked = closed, locked
+}
+
+// Authored returns a copy of the door in the state it was built with, for saving.
+func (d *Door) Authored() *Door {
+	authored := *d
+	authored.IsClosed, authored.IsLocked = d.authoredClosed, d.authoredLocked
+
+	return &authored
+}
+
 func (r *Room) AddMobInstance(m *MobInstance) {
 	r.Lock()
 	defer r.Unlock()
 
-	m.RoomID = r.ID
+	m.RoomID = r.QualifiedID()
+	m.Room = r
 
 	r.MobInstances[m.InstanceID] = m
 }
@@ -272,6 +396,7 @@ func (r *Room) RemoveMobInstance(m *MobInstance) {
 	defer r.Unlock()
 
 	m.RoomID = ""
+	m.Room = nil
 
 	delete(r.MobInstances, m.InstanceID)
 }
@@ -290,6 +415,49 @@ func (r *Room) Broadcast(msg string, excludeIDs []string) {
 	}
 }
 
+// BroadcastAbout sends a message describing what subject is doing to everyone in the room who can see them.
+func (r *Room) BroadcastAbout(subject *Character, msg string, excludeIDs []string) {
+	excludes := make(map[string]bool)
+
+	for _, id := range excludeIDs {
+		excludes[id] = true
+	}
+
+	for _, char := range r.Characters {
+		if _, ok := excludes[char.ID]; ok || !char.CanSee(subject) {
+			continue
+		}
+		char.Send(msg)
+	}
+}
+
+// BroadcastFrom sends a message from sender to everyone in the room, skipping anyone ignoring them or who
+// can't see them. It returns the characters the message was delivered to.
+func (r *Room) BroadcastFrom(sender *Character, msg string, excludeIDs []string) []*Character {
+	return r.BroadcastFromFunc(sender, func(*Character) string { return msg }, excludeIDs)
+}
+
+// BroadcastFromFunc is like BroadcastFrom but renders the message separately for each listener.
+func (r *Room) BroadcastFromFunc(sender *Character, render func(listener *Character) string, excludeIDs []string) []*Character {
+	excludes := make(map[string]bool)
+
+	for _, id := range excludeIDs {
+		excludes[id] = true
+	}
+
+	var recipients []*Character
+	for _, char := range r.Characters {
+		if _, ok := excludes[char.ID]; ok || !char.CanSee(sender) {
+			continue
+		}
+		if char.SendFrom(sender, render(char)) {
+			recipients = append(recipients, char)
+		}
+	}
+
+	return recipients
+}
+
 // // Event functions
 // func (r *Room) onRoomCharacterEnter(arguments ...interface{}) {
 // 	slog.Debug("Room character enter event",
@@ -345,20 +513,76 @@ func (r *Room) Broadcast(msg string, excludeIDs []string) {
 // 	r.Broadcast("A mob has left the room", []string{arg.Mob.ID})
 // }
 
-// RenderRoom renders the room to a string for the player.
+// QualifiedID returns the room's area-qualified ID.
+func (r *Room) QualifiedID() string {
+	return QualifyID(r.AreaID, r.ID)
+}
+
+// Refresh copies the data loaded from the room's file into the room, keeping the characters, mobs and
+// items that are in it and whether its doors are open or locked, unless the door was rebuilt differently.
+func (r *Room) Refresh(from *Room) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.Title = from.Title
+	r.Description = from.Description
+	r.Landmark = from.Landmark
+	r.Light = from.Light
+	r.Tags = from.Tags
+	r.Bias = from.Bias
+	r.Corrdinates = from.Corrdinates
+	r.Spawns = from.Spawns
+	r.File = from.File
+
+	// Doors that are still built the same way stay as players left them
+	for dir, exit := range from.Exits {
+		old, ok := r.Exits[dir]
+		if !ok || old.Door == nil || exit.Door == nil {
+			continue
+		}
+		if built := old.Door.Authored(); built.IsClosed == exit.Door.IsClosed && built.IsLocked == exit.Door.IsLocked {
+			exit.Door.IsClosed, exit.Door.IsLocked = old.Door.IsClosed, old.Door.IsLocked
+		}
+	}
+	r.Exits = from.Exits
+}
+
+// RenderRoom renders the room to a string for the player. Rooms too dark for the character to see are
+// only described by what they can hear, and dim ones hide the details of who and what is there.
 func RenderRoom(user *Account, char *Character, room *Room) string {
 	var builder strings.Builder
-	if char.Role == CharacterRoleAdmin {
+	if char.HasPermission(PermissionWorldRoomIDs) {
 		builder.WriteString(cfmt.Sprintf("{{[}}::white|bold{{%s}}::yellow{{]}}::white|bold", char.Room.ID))
 		builder.WriteString(CRLF)
 	}
 
+	if !char.CanSeeIn(char.Room) {
+		builder.WriteString(cfmt.Sprintf("{{Darkness}}::blue|bold") + CRLF + HT)
+		builder.WriteString("It is too dark to see anything." + CRLF)
+		builder.WriteString("" + CRLF)
+		if len(char.Room.MobInstances) > 0 || len(char.Room.Characters) > 1 {
+			builder.WriteString("You hear movement nearby." + CRLF)
+			builder.WriteString("" + CRLF)
+		}
+
+		return builder.String()
+	}
+
 	builder.WriteString(cfmt.Sprintf("{{%s}}::cyan|bold", char.Room.Title))
-	if len(char.Room.Tags) > 0 {
-		builder.WriteString(cfmt.Sprintf(" {{[}}::white|bold{{%s}}::green{{]}}::white|bold", strings.Join(char.Room.Tags, ", ")))
+	// Include the tags every room in the area has
+	if tags := char.Room.AllTags(); len(tags) > 0 {
+		builder.WriteString(cfmt.Sprintf(" {{[}}::white|bold{{%s}}::green{{]}}::white|bold", strings.Join(tags, ", ")))
+	}
+	if char.AutoMap {
+		builder.WriteString(CRLF)
+		builder.WriteString(RenderBesideMap(char, cfmt.Sprint(char.Room.Description), 80))
+	} else {
+		builder.WriteString(CRLF + HT)
+		builder.WriteString(wordwrap.String(cfmt.Sprint(char.Room.Description), 80) + "" + CRLF)
+	}
+	if weather := RenderWeather(char.Room); weather != "" {
+		builder.WriteString(wordwrap.String(weather, 80) + CRLF)
 	}
-	builder.WriteString(CRLF + HT)
-	builder.WriteString(wordwrap.String(cfmt.Sprint(char.Room.Description), 80) + "" + CRLF)
 	builder.WriteString("" + CRLF)
 	builder.WriteString(RenderEntitiesInRoom(char) + "" + CRLF)
 	builder.WriteString("" + CRLF)
@@ -377,11 +601,25 @@ func RenderRoom(user *Account, char *Character, room *Room) string {
 func RenderEntitiesInRoom(char *Character) string {
 	var builder strings.Builder
 
-	// Total entity count minus the character itself
-	entityCount := len(char.Room.Characters) - 1 + len(char.Room.MobInstances)
+	// Total entity count minus the character itself and anyone they can't see
+	entityCount := len(char.Room.MobInstances)
+	for _, c := range char.Room.Characters {
+		if c.Name != char.Name && char.CanSee(c) {
+			entityCount++
+		}
+	}
+
+	// In dim light there are only shapes
+	if !char.CanMakeOut(char.Room) {
+		if entityCount == 0 {
+			return cfmt.Sprintf("You are the only one here.")
+		}
+		return cfmt.Sprintf("You can make out {{%s}}::bold in the gloom.", pluralizer.PluralizeNounPhrase("shadowy figure", entityCount))
+	}
+
 	entityDescriptions := []string{}
 	for _, c := range char.Room.Characters {
-		if c.Name != char.Name {
+		if c.Name != char.Name && char.CanSee(c) {
 			metatype := EntityMgr.GetMetatype(c.MetatypeID)
 			entityDescriptions = append(entityDescriptions, cfmt.Sprintf(
 				"{{%s (%s)}}::cyan|bold", c.Name, metatype.Name))
@@ -420,6 +658,10 @@ func RenderItemsInRoom(char *Character) string {
 		itemNameCounts[bp.Name]++
 	}
 
+	if itemCount > 0 && !char.CanMakeOut(char.Room) {
+		return cfmt.Sprint("Something lies scattered about, but it's too dim to make out.")
+	}
+
 	// Build the item description
 	if itemCount > 0 {
 		// Introductory text (white)
@@ -445,6 +687,10 @@ func RenderRoomExits(char *Character) string {
 	}
 	exitStrings := make([]string, 0, len(char.Room.Exits))
 	for dir, exit := range char.Room.Exits {
+		if !exit.VisibleTo(char, char.Room) {
+			continue
+		}
+
 		// Determine exit description based on exit type
 		var exitDescription string
 		switch exit.Type {
@@ -493,6 +739,15 @@ func RenderRoomExits(char *Character) string {
 			exitStrings = append(exitStrings,
 				cfmt.Sprintf("To the {{%s}}::yellow, there is %s leading to {{%s}}::yellow.", dir, exitDescription, exit.Room.Title))
 		}
+
+		// Builders see hidden exits without finding them
+		if exit.Hidden && !char.HasFoundExit(char.Room, dir) {
+			exitStrings[len(exitStrings)-1] += cfmt.Sprint(" {{[hidden]}}::magenta")
+		}
+	}
+
+	if len(exitStrings) == 0 {
+		return cfmt.Sprintf("{{There are no exits}}::red")
 	}
 
 	builder.WriteString(strings.Join(exitStrings, " "))
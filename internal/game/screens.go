@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Jasrags/NewMUD/pluralizer"
 	"github.com/gliderlabs/ssh"
 	"github.com/i582/cfmt/cmd/cfmt"
 	"github.com/spf13/viper"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 const (
@@ -20,6 +24,10 @@ const (
 	StateRegistration    = "registration"
 	StateMainMenu        = "main_menu"
 	StateChangePassword  = "change_password"
+	StateManageKeys      = "manage_keys"
+	StateChangeEmail     = "change_email"
+	StatePasswordReset   = "password_reset"
+	StateAccountDelete   = "account_delete"
 	StateCharacterSelect = "character_select"
 	StateCharacterCreate = "character_create"
 	StateCharacterDelete = "character_delete"
@@ -51,9 +59,23 @@ func PromptWelcome(s ssh.Session) string {
 }
 
 func PromptLogin(s ssh.Session) (string, *Account) {
+	// Skip the password prompt when the session authenticated with a key registered to the account.
+	if u := AccountMgr.GetByPublicKey(s.User(), s.PublicKey()); u != nil {
+		slog.Info("Public key login",
+			slog.String("username", u.Username),
+			slog.String("remote_address", s.RemoteAddr().String()))
+
+		WriteStringF(s, "{{Authenticated with SSH key. Welcome back, %s!}}::green|bold"+CRLF, u.Username)
+		if !promptRestoreAccount(s, u) {
+			return StateQuit, nil
+		}
+
+		return StateMainMenu, u
+	}
+
 	for {
 		// Prompt for username or registration.
-		WriteString(s, "{{Enter your username to continue or type}}::white {{new}}::green|bold {{to register:}}::white"+CRLF)
+		WriteString(s, "{{Enter your username to continue, type}}::white {{new}}::green|bold {{to register or}}::white {{forgot}}::green|bold {{to reset your password:}}::white"+CRLF)
 		WriteString(s, "{{Username:}}::white|bold ")
 
 		username, err := InputPrompt(s, "")
@@ -67,6 +89,11 @@ func PromptLogin(s ssh.Session) (string, *Account) {
 			return StateRegistration, nil
 		}
 
+		// Handle password reset.
+		if strings.EqualFold(username, "forgot") {
+			return StatePasswordReset, nil
+		}
+
 		// Prompt for password.
 		WriteString(s, "{{Password:}}::white|bold ")
 		password, err := PasswordPrompt(s, "")
@@ -85,12 +112,35 @@ func PromptLogin(s ssh.Session) (string, *Account) {
 		// TODO: Check if the user is already logged in.
 		// TODO: Check if the user is banned.
 
+		if !promptRestoreAccount(s, u) {
+			return StateQuit, nil
+		}
+
 		// Login successful.
 		WriteStringF(s, "{{Welcome back, %s!}}::green|bold"+CRLF, username)
 		return StateMainMenu, u
 	}
 }
 
+// promptRestoreAccount offers to restore an account that is pending deletion, returning false if the
+// user declines.
+func promptRestoreAccount(s ssh.Session, u *Account) bool {
+	if !u.IsDeleted() {
+		return true
+	}
+
+	WriteStringF(s, "{{This account is scheduled for deletion on %s.}}::yellow|bold"+CRLF, u.PurgeAt().Format(time.RFC1123))
+	WriteString(s, "{{Restore the account and its characters?}}::white|bold"+CRLF)
+	if !YesNoPrompt(s, false) {
+		return false
+	}
+
+	RestoreAccount(u)
+	WriteString(s, "{{Your account has been restored.}}::green"+CRLF)
+
+	return true
+}
+
 func PromptRegistration(s ssh.Session) (string, *Account) {
 	slog.Debug("Registration state",
 		slog.String("remote_address", s.RemoteAddr().String()),
@@ -155,9 +205,31 @@ func PromptRegistration(s ssh.Session) (string, *Account) {
 		break // Passwords are valid and match.
 	}
 
+	// Prompt for an optional email address used for password resets.
+	var email string
+	for {
+		var err error
+		email, err = InputPrompt(s, cfmt.Sprint("{{Enter your email address (optional, used for password resets):}}::white|bold "))
+		if err != nil {
+			return StateError, nil
+		}
+
+		if email == "" {
+			break
+		}
+
+		if err := ValidateEmail(email); err != nil {
+			WriteString(s, cfmt.Sprintf("{{Invalid email: %s}}::red"+CRLF, err.Error()))
+			continue
+		}
+
+		break
+	}
+
 	// Create a new user account.
 	u := NewAccount()
 	u.Username = username
+	u.Email = email
 	u.SetPassword(password)
 	u.Save()
 	AccountMgr.AddAccount(u)
@@ -166,12 +238,18 @@ func PromptRegistration(s ssh.Session) (string, *Account) {
 }
 
 func PromptMainMenu(s ssh.Session, a *Account) string {
+	if unread := a.UnreadMailCount(); unread > 0 {
+		WriteStringF(s, "{{You have %d unread mail %s.}}::cyan|bold"+CRLF, unread, pluralizer.PluralizeNoun("message", unread))
+	}
 
 	options := []MenuOption{
 		{"Enter Game", "enter_game", "Enter Game"},
 		{"Create Character", "create_character", "Create Character"},
 		{"Delete Character", "delete_character", "Delete a character"},
 		{"Change Password", "change_password", "Change your password"},
+		{"Change Email", "change_email", "Change the email address used for password resets"},
+		{"Manage SSH Keys", "manage_keys", "Register SSH public keys to log in without a password"},
+		{"Delete Account", "delete_account", "Delete your account and all of its characters"},
 		{"Quit", "quit", "Exit the game"},
 	}
 
@@ -195,6 +273,12 @@ func PromptMainMenu(s ssh.Session, a *Account) string {
 			return StateCharacterDelete
 		case "change_password":
 			return StateChangePassword
+		case "change_email":
+			return StateChangeEmail
+		case "manage_keys":
+			return StateManageKeys
+		case "delete_account":
+			return StateAccountDelete
 		case "quit":
 			return StateQuit
 		}
@@ -247,6 +331,248 @@ func PromptChangePassword(s ssh.Session, a *Account) string {
 	}
 }
 
+func PromptChangeEmail(s ssh.Session, a *Account) string {
+	if a.Email != "" {
+		WriteStringF(s, "{{Your current email address is}}::white {{%s}}::cyan"+CRLF, a.Email)
+	}
+
+	for {
+		email, err := InputPrompt(s, cfmt.Sprint("{{Enter your new email address (blank to remove it):}}::white|bold "))
+		if err != nil {
+			return StateError
+		}
+
+		if email != "" {
+			if err := ValidateEmail(email); err != nil {
+				WriteString(s, cfmt.Sprintf("{{Invalid email: %s}}::red"+CRLF, err.Error()))
+				continue
+			}
+		}
+
+		a.Email = email
+		a.Save()
+
+		WriteString(s, "{{Email address updated.}}::green"+CRLF)
+		return StateMainMenu
+	}
+}
+
+func PromptPasswordReset(s ssh.Session) string {
+	WriteString(s, "{{Password reset}}::green"+CRLF)
+
+	username, err := InputPrompt(s, cfmt.Sprint("{{Enter your username:}}::white|bold "))
+	if err != nil {
+		return StateError
+	}
+
+	// Always respond the same way so the prompt can't be used to discover accounts or addresses.
+	u := AccountMgr.GetByUsername(username)
+	if u != nil && u.Email != "" {
+		token, err := u.GeneratePasswordResetToken(viper.GetDuration("server.password_reset_token_ttl"))
+		if err != nil {
+			slog.Error("failed to generate password reset token",
+				slog.Any("error", err))
+			return StateLogin
+		}
+		u.Save()
+
+		body := fmt.Sprintf("A password reset was requested for %s.\r\n\r\nYour reset token is: %s\r\n\r\nIt expires in %s. If you did not request this you can ignore this message.\r\n",
+			u.Username, token, viper.GetDuration("server.password_reset_token_ttl"))
+		if err := Mailer.Send(u.Email, "Password reset", body); err != nil {
+			slog.Error("failed to send password reset mail",
+				slog.String("username", u.Username),
+				slog.Any("error", err))
+		}
+	}
+	WriteString(s, "{{If that account has an email address, a reset token has been sent to it.}}::yellow"+CRLF)
+
+	token, err := InputPrompt(s, cfmt.Sprint("{{Enter your reset token (blank to cancel):}}::white|bold "))
+	if err != nil {
+		return StateError
+	}
+
+	if token == "" {
+		return StateLogin
+	}
+
+	if u == nil || !u.CheckPasswordResetToken(token) {
+		slog.Warn("Invalid password reset attempt", slog.String("username", username))
+		WriteString(s, "{{Invalid or expired reset token.}}::red"+CRLF)
+		return StateLogin
+	}
+
+	for {
+		password, err := PasswordPrompt(s, cfmt.Sprint("{{Enter your new password:}}::white|bold "))
+		if err != nil {
+			return StateError
+		}
+
+		if err := ValidatePassword(password); err != nil {
+			WriteString(s, cfmt.Sprintf("{{Invalid password: %s}}::red"+CRLF, err.Error()))
+			continue
+		}
+
+		confirmPassword, err := PasswordPrompt(s, cfmt.Sprint("{{Confirm your new password:}}::white|bold "))
+		if err != nil {
+			return StateError
+		}
+
+		if password != confirmPassword {
+			WriteString(s, "{{Passwords do not match.}}::red"+CRLF)
+			continue
+		}
+
+		u.SetPassword(password)
+		u.ClearPasswordResetToken()
+		u.Save()
+
+		slog.Info("Password reset",
+			slog.String("username", u.Username))
+
+		WriteString(s, "{{Your password has been reset, please log in.}}::green"+CRLF)
+		return StateLogin
+	}
+}
+
+func PromptAccountDelete(s ssh.Session, a *Account) string {
+	password, err := PasswordPrompt(s, cfmt.Sprint("{{Enter your password to confirm:}}::white|bold "))
+	if err != nil {
+		return StateError
+	}
+
+	if !a.CheckPassword(password) {
+		WriteString(s, "{{Invalid password.}}::red"+CRLF)
+		return StateMainMenu
+	}
+
+	grace := viper.GetDuration("server.account_deletion_grace_period")
+	WriteStringF(s, CRLF+"{{Your account and all of its characters will be permanently deleted in %s. Logging in before then lets you restore it.}}::red|bold"+CRLF, grace)
+	if !YesNoPrompt(s, false) {
+		WriteString(s, "{{Account deletion canceled.}}::yellow"+CRLF)
+		return StateMainMenu
+	}
+
+	DeleteAccount(a)
+
+	WriteString(s, "{{Your account has been scheduled for deletion. Goodbye.}}::green"+CRLF)
+	return StateQuit
+}
+
+// DeleteAccount soft deletes the account and its characters.
+func DeleteAccount(a *Account) {
+	a.MarkDeleted()
+	a.Save()
+
+	for _, name := range a.Characters {
+		c := CharacterMgr.GetCharacterByName(name)
+		if c == nil {
+			continue
+		}
+
+		t := time.Now()
+		c.DeletedAt = &t
+		c.Save()
+	}
+}
+
+// RestoreAccount cancels the pending deletion of the account and its characters.
+func RestoreAccount(a *Account) {
+	a.Restore()
+	a.Save()
+
+	for _, name := range a.Characters {
+		c := CharacterMgr.GetCharacterByName(name)
+		if c == nil {
+			continue
+		}
+
+		c.DeletedAt = nil
+		c.Save()
+	}
+}
+
+func PromptManageKeys(s ssh.Session, a *Account) string {
+	options := []MenuOption{
+		{"List Keys", "list", "List the SSH public keys registered to your account"},
+		{"Add Key", "add", "Register a public key in authorized_keys format"},
+		{"Remove Key", "remove", "Remove a registered public key"},
+		{"Back to Main Menu", "back", "Return to the main menu"},
+	}
+
+	for {
+		choice, err := PromptForMenu(s, "Manage SSH Keys", options)
+		if err != nil {
+			return StateError
+		}
+
+		switch choice {
+		case "list":
+			renderPublicKeys(s, a)
+		case "add":
+			WriteString(s, "{{Paste your public key (e.g. the contents of ~/.ssh/id_ed25519.pub):}}::white|bold"+CRLF)
+			line, err := InputPrompt(s, "")
+			if err != nil {
+				return StateError
+			}
+
+			if line == "" {
+				continue
+			}
+
+			key, err := a.AddPublicKey(line)
+			if err != nil {
+				WriteStringF(s, "{{Unable to add key: %s}}::red"+CRLF, err.Error())
+				continue
+			}
+			a.Save()
+
+			WriteStringF(s, "{{Added key %s.}}::green"+CRLF, gossh.FingerprintSHA256(key))
+			WriteStringF(s, "{{Connect as}}::white {{%s}}::cyan {{with this key to skip the password prompt.}}::white"+CRLF, a.Username)
+		case "remove":
+			if !renderPublicKeys(s, a) {
+				continue
+			}
+
+			input, err := InputPrompt(s, cfmt.Sprint("{{Enter the number of the key to remove:}}::white|bold "))
+			if err != nil {
+				return StateError
+			}
+
+			index, err := strconv.Atoi(input)
+			if err != nil {
+				WriteString(s, "{{Invalid selection.}}::red"+CRLF)
+				continue
+			}
+
+			if err := a.RemovePublicKey(index - 1); err != nil {
+				WriteStringF(s, "{{Unable to remove key: %s}}::red"+CRLF, err.Error())
+				continue
+			}
+			a.Save()
+
+			WriteString(s, "{{Key removed.}}::green"+CRLF)
+		case "back":
+			return StateMainMenu
+		}
+	}
+}
+
+// renderPublicKeys lists the account's registered keys and reports whether there were any.
+func renderPublicKeys(s ssh.Session, a *Account) bool {
+	fingerprints := a.PublicKeyFingerprints()
+	if len(fingerprints) == 0 {
+		WriteString(s, "{{You have no SSH keys registered.}}::yellow"+CRLF)
+		return false
+	}
+
+	WriteString(s, CRLF+"{{Registered SSH keys:}}::white|bold"+CRLF)
+	for i, fingerprint := range fingerprints {
+		WriteStringF(s, "{{%d}}::green|bold. {{%s}}::cyan"+CRLF, i+1, fingerprint)
+	}
+
+	return true
+}
+
 func PromptCharacterCreate(s ssh.Session, a *Account) (string, *Character) {
 	options := []MenuOption{
 		{"Create a Pre-Generated Character", "pregen", "Select from predefined character archetypes"},
@@ -287,6 +613,7 @@ func PromptPregenCharacterMenu(s ssh.Session, a *Account, c *Character) (string,
 		{"Set Character Template", "template", "Select a pre-generated character template"},
 		{"Set Character Name", "name", "Enter the character's name"},
 		{"Set Character Sex", "sex", "Select the character's sex"},
+		{"Set Native Languages", "languages", "Select the character's native languages"},
 		{"Set Character Age", "age", "Enter the character's age"},
 		{"Set Character Height", "height", "Enter the character's height in cm"},
 		{"Set Character Weight", "weight", "Enter the character's weight in kg"},
@@ -325,6 +652,12 @@ func PromptPregenCharacterMenu(s ssh.Session, a *Account, c *Character) (string,
 				continue
 			}
 			c = newChar
+		case "languages":
+			state, newChar := PromptSetCharacterLanguages(s, a, c)
+			if state == StateError {
+				continue
+			}
+			c = newChar
 		case "age":
 			state, newChar := PromptSetCharacterAge(s, a, c)
 			if state == StateError {
@@ -364,6 +697,7 @@ func PromptPregenCharacterMenu(s ssh.Session, a *Account, c *Character) (string,
 			}
 
 			// Save the character
+			c.AccountID = a.ID
 			a.Characters = append(a.Characters, c.Name)
 			CharacterMgr.AddCharacter(c)
 			c.Save()
@@ -410,6 +744,18 @@ func displayCharacterProgress(s ssh.Session, char *Character) {
 		progress.WriteString(fmt.Sprintf(setString, char.Sex))
 	}
 
+	// Native Languages
+	progress.WriteString(fmt.Sprintf(titleString, "Native Languages"))
+	if len(char.NativeLanguages) == 0 {
+		progress.WriteString(unsetString)
+	} else {
+		names := make([]string, len(char.NativeLanguages))
+		for i, id := range char.NativeLanguages {
+			names[i] = languageName(id)
+		}
+		progress.WriteString(fmt.Sprintf(setString, strings.Join(names, ", ")))
+	}
+
 	// Age
 	progress.WriteString(fmt.Sprintf(titleString, "Age"))
 	if char.Age <= 0 {
@@ -467,6 +813,9 @@ func validateCharacterFields(char *Character) []string {
 	if strings.TrimSpace(char.Sex) == "" {
 		missing = append(missing, "Character Sex")
 	}
+	if len(char.NativeLanguages) == 0 {
+		missing = append(missing, "Native Languages")
+	}
 	if char.Age <= 0 {
 		missing = append(missing, "Character Age")
 	}
@@ -560,6 +909,7 @@ func PromptSelectPregenTemplate(s ssh.Session, a *Account, char *Character) (str
 		// Update skills and qualities from the template.
 		char.Skills = pregen.Skills
 		char.Qualtities = pregen.Qualtities
+		char.NativeLanguages = slices.Clone(pregen.NativeLanguages)
 
 		// Successfully updated; return to the central menu.
 		return "", char
@@ -580,6 +930,12 @@ func PromptSetCharacterDetails(s ssh.Session, a *Account, char *Character) (stri
 		char = updatedChar
 	}
 
+	if state, updatedChar := PromptSetCharacterLanguages(s, a, char); state == StateError {
+		return state, nil
+	} else {
+		char = updatedChar
+	}
+
 	if state, updatedChar := PromptSetCharacterAge(s, a, char); state == StateError {
 		return state, nil
 	} else {
@@ -611,6 +967,7 @@ func PromptSetCharacterDetails(s ssh.Session, a *Account, char *Character) (stri
 	}
 
 	// Save and return
+	char.AccountID = a.ID
 	a.Characters = append(a.Characters, char.Name)
 	CharacterMgr.AddCharacter(char)
 	char.Save()
@@ -647,11 +1004,55 @@ func PromptSetCharacterName(s ssh.Session, a *Account, char *Character) (string,
 	}
 }
 
+// PromptSetCharacterLanguages selects the character's native languages, one plus an extra language for
+// each rating of the bilingual quality.
+func PromptSetCharacterLanguages(s ssh.Session, a *Account, char *Character) (string, *Character) {
+	languages := EntityMgr.GetLanguages()
+	count := char.GetMaxNativeLanguages()
+
+	var selected []string
+	for len(selected) < count {
+		options := make([]MenuOption, 0, len(languages))
+		for _, bp := range languages {
+			if slices.Contains(selected, bp.ID) {
+				continue
+			}
+			options = append(options, MenuOption{
+				DisplayText: bp.Name,
+				Value:       bp.ID,
+				Description: "Speak " + bp.Name + " natively",
+			})
+		}
+
+		title := "Select Your Character's Native Language"
+		if count > 1 {
+			title = fmt.Sprintf("Select Native Language %d of %d", len(selected)+1, count)
+		}
+
+		choice, err := PromptForMenu(s, title, options)
+		if err != nil {
+			return StateError, nil
+		}
+
+		selected = append(selected, choice)
+	}
+
+	WriteStringF(s, CRLF+"{{Native Languages: %s}}::cyan"+CRLF, strings.Join(selected, ", "))
+	if !YesNoPrompt(s, true) {
+		return "", char
+	}
+
+	char.NativeLanguages = selected
+	char.Language = ""
+
+	return "", char
+}
+
 func PromptSetCharacterSex(s ssh.Session, a *Account, char *Character) (string, *Character) {
 	options := []MenuOption{
-		{"Male", "male", "Male character"},
-		{"Female", "female", "Female character"},
-		{"Non-Binary", "non_binary", "Non-binary character"},
+		{"Male", SexMale, "Male character"},
+		{"Female", SexFemale, "Female character"},
+		{"Non-Binary", SexNonBinary, "Non-binary character"},
 	}
 
 	for {
@@ -797,8 +1198,10 @@ func PromptEnterGame(s ssh.Session, a *Account) (string, *Character) {
 			continue
 		}
 
-		// Set the session connection for the character.
+		// Set the session connection and account for the character.
 		c.Conn = s
+		c.Account = a
+		c.AccountID = a.ID
 
 		// Retrieve the starting room ID from configuration.
 		startingRoomID := viper.GetString("server.starting_room")
@@ -812,6 +1215,14 @@ func PromptEnterGame(s ssh.Session, a *Account) (string, *Character) {
 			c.Room = EntityMgr.GetRoom(c.RoomID)
 		}
 
+		// Characters that idled into the void pick up where they left off.
+		if c.VoidRoomID != "" {
+			if room := EntityMgr.GetRoom(c.VoidRoomID); room != nil {
+				c.SetRoom(room)
+			}
+			c.VoidRoomID = ""
+		}
+
 		// Set ItemBlueprint for each item in inventory and equipment
 		for _, item := range c.Inventory.Items {
 			bp := EntityMgr.GetItemBlueprintByInstance(item)
@@ -847,6 +1258,19 @@ func PromptEnterGame(s ssh.Session, a *Account) (string, *Character) {
 
 		// Notify the user and proceed into the game.
 		WriteString(s, cfmt.Sprintf("{{Entering the game as %s...}}::green|bold"+CRLF, c.Name))
+		if unread := a.UnreadMailCount(); unread > 0 {
+			WriteStringF(s, "{{You have %d unread mail %s. Type 'mail' to read them.}}::cyan"+CRLF, unread, pluralizer.PluralizeNoun("message", unread))
+		}
+
+		// Deliver tells sent while the character was offline.
+		if tells := c.TakeOfflineTells(); len(tells) > 0 {
+			WriteString(s, "{{While you were away:}}::white|bold"+CRLF)
+			for _, t := range tells {
+				WriteString(s, t.Render(c))
+				c.ReceiveTell(t.From)
+			}
+			c.Save()
+		}
 		return StateGameLoop, c
 	}
 }
@@ -924,6 +1348,7 @@ func removeCharacterFromAccount(characters []string, name string) []string {
 func PromptGameLoop(s ssh.Session, a *Account, c *Character) string {
 	// Add the character to their current room.
 	c.Room.AddCharacter(c)
+	SessionMgr.SetCharacter(s, c)
 
 	// Render the room on initial entry.
 	WriteString(s, RenderRoom(a, c, c.Room))
@@ -953,6 +1378,18 @@ func PromptGameLoop(s ssh.Session, a *Account, c *Character) string {
 			return StateExitGame
 		}
 
+		// Any command other than afk brings the character back from being away.
+		if afk, _ := c.IsAFK(); afk && !strings.EqualFold(strings.Fields(input)[0], "afk") {
+			returnFromAFK(c)
+		}
+
+		// Coming back to the keyboard returns characters that idled into the void.
+		if c.ReturnFromVoid() {
+			c.Save()
+			WriteString(s, RenderRoom(a, c, c.Room))
+			WriteString(s, CRLF)
+		}
+
 		// Parse and execute the entered command.
 		CommandMgr.ParseAndExecute(s, input, a, c, c.Room)
 	}
@@ -961,11 +1398,24 @@ func PromptGameLoop(s ssh.Session, a *Account, c *Character) string {
 func PromptExitGame(s ssh.Session, a *Account, c *Character) string {
 	// Broadcast that the character is leaving the game.
 	exitMessage := cfmt.Sprintf("%s leaves the game."+CRLF, c.Name)
-	c.Room.Broadcast(exitMessage, []string{c.ID})
+	c.Room.BroadcastAbout(c, exitMessage, []string{c.ID})
 
 	// Send a goodbye message to the user.
 	WriteStringF(s, "{{Goodbye, %s!}}::green"+CRLF, a.Username)
 
+	// Leave any team the character was in.
+	if t, err := TeamMgr.Leave(c); err == nil {
+		notifyTeam(t, c, cfmt.Sprintf("{{%s has left the team.}}::yellow"+CRLF, c.Name))
+	}
+
+	// Away status only lasts for the session.
+	c.ClearAFK()
+
+	// Take the character out of the world and save where they left off.
+	SessionMgr.SetCharacter(s, nil)
+	c.Room.RemoveCharacter(c)
+	c.Save()
+
 	// Mark the character as offline.
 	CharacterMgr.SetCharacterOffline(c)
 
@@ -1,14 +1,20 @@
 package game
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
 	"log/slog"
 	"net"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/gliderlabs/ssh"
 	"github.com/spf13/viper"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 const ()
@@ -40,12 +46,24 @@ func (s *GameServer) Init() {
 	// Set game server properties
 	s.TickDuration = viper.GetDuration("server.tick_duration")
 
+	Mailer = NewMailerFromConfig()
+
 	go GameTimeMgr.StartTicker(s.TickDuration)
+	go SessionMgr.StartIdleChecker(s.TickDuration)
 
 	EntityMgr.LoadDataFiles()
+	if viper.GetBool("data.watch_areas") {
+		go EntityMgr.WatchAreas()
+	}
+	PermissionMgr.LoadDataFiles()
 	AccountMgr.LoadDataFiles()
 	CharacterMgr.LoadDataFiles()
 
+	AccountMgr.PurgeDeletedAccounts()
+	go AccountMgr.StartPurger(time.Hour)
+
+	ChannelMgr.LoadChannels()
+	SocialMgr.LoadDataFiles()
 	RegisterCommands()
 }
 
@@ -58,10 +76,21 @@ func (s *GameServer) Start() {
 	slog.Info("Starting server",
 		slog.String("address", address))
 
+	hostKey, err := LoadOrCreateHostKey(viper.GetString("server.host_key_path"))
+	if err != nil {
+		slog.Error("Error loading host key",
+			slog.String("host_key_path", viper.GetString("server.host_key_path")),
+			slog.Any("error", err))
+		return
+	}
+
+	// Idle sessions are handled by the SessionMgr so players can be warned before being disconnected.
 	server := &ssh.Server{
-		Addr: address,
-		// IdleTimeout:              viper.GetDuration("server.idle_timeout"), // TODO: reenable timeout later when we fix the connection close issues
-		Handler: handleConnection,
+		Addr:                       address,
+		HostSigners:                []ssh.Signer{hostKey},
+		Handler:                    handleConnection,
+		PublicKeyHandler:           publicKeyHandler,
+		KeyboardInteractiveHandler: keyboardInteractiveHandler,
 		// ConnCallback:             ConnCallback,
 		// ConnectionFailedCallback: ConnectionFailedCallback,
 	}
@@ -75,6 +104,59 @@ func (s *GameServer) Start() {
 	}
 }
 
+// publicKeyHandler only accepts keys registered to the account named by the SSH user, so a
+// successfully authenticated key always belongs to that account.
+func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
+	return AccountMgr.GetByPublicKey(ctx.User(), key) != nil
+}
+
+// keyboardInteractiveHandler lets clients without a registered key through to the password login.
+// Any key offered during an earlier failed attempt is cleared so it can't be mistaken for a verified one.
+func keyboardInteractiveHandler(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+	ctx.SetValue(ssh.ContextKeyPublicKey, nil)
+	return true
+}
+
+// LoadOrCreateHostKey loads the server's host key from path, generating and saving a new
+// ed25519 key the first time so clients see the same host key across restarts.
+func LoadOrCreateHostKey(path string) (ssh.Signer, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no host key path configured")
+	}
+
+	if FileExists(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return gossh.ParsePrivateKey(data)
+	}
+
+	slog.Info("Generating new host key",
+		slog.String("host_key_path", path))
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := gossh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, err
+	}
+
+	return gossh.NewSignerFromKey(privateKey)
+}
+
 func (s *GameServer) Stop() {
 	// Stop the game server
 }
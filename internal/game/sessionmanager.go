@@ -0,0 +1,240 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/i582/cfmt/cmd/cfmt"
+	"github.com/spf13/viper"
+)
+
+var (
+	SessionMgr = NewSessionManager()
+)
+
+type (
+	Session struct {
+		ID          string
+		Conn        ssh.Session
+		RemoteIP    string
+		ConnectedAt time.Time
+		LastInputAt time.Time
+		IdleWarned  bool
+		Character   *Character
+	}
+
+	SessionManager struct {
+		sync.RWMutex
+
+		sessions map[string]*Session
+	}
+)
+
+func NewSessionManager() *SessionManager {
+	return &SessionManager{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// remoteIP returns the IP address portion of the session's remote address.
+func remoteIP(s ssh.Session) string {
+	host, _, err := net.SplitHostPort(s.RemoteAddr().String())
+	if err != nil {
+		return s.RemoteAddr().String()
+	}
+
+	return host
+}
+
+// AddSession registers a new connection, enforcing the connection cap and the per IP session limit. The cap
+// counts every connection, including ones still logging in or choosing a character.
+func (mgr *SessionManager) AddSession(s ssh.Session) (*Session, error) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	maxConnections := viper.GetInt("server.max_connections")
+	if maxConnections > 0 && len(mgr.sessions) >= maxConnections {
+		return nil, fmt.Errorf("the server is full, please try again later")
+	}
+
+	ip := remoteIP(s)
+	maxPerIP := viper.GetInt("server.max_sessions_per_ip")
+	if maxPerIP > 0 {
+		count := 0
+		for _, sess := range mgr.sessions {
+			if sess.RemoteIP == ip {
+				count++
+			}
+		}
+		if count >= maxPerIP {
+			return nil, fmt.Errorf("too many connections from your address")
+		}
+	}
+
+	t := time.Now()
+	sess := &Session{
+		ID:          s.Context().SessionID(),
+		Conn:        s,
+		RemoteIP:    ip,
+		ConnectedAt: t,
+		LastInputAt: t,
+	}
+	mgr.sessions[sess.ID] = sess
+
+	slog.Debug("Added session",
+		slog.String("session_id", sess.ID),
+		slog.String("remote_ip", ip),
+		slog.Int("count", len(mgr.sessions)))
+
+	return sess, nil
+}
+
+func (mgr *SessionManager) RemoveSession(s ssh.Session) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	slog.Debug("Removing session",
+		slog.String("session_id", s.Context().SessionID()))
+
+	delete(mgr.sessions, s.Context().SessionID())
+}
+
+func (mgr *SessionManager) GetSession(s ssh.Session) *Session {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return mgr.sessions[s.Context().SessionID()]
+}
+
+// Touch records input from the session, resetting its idle timer.
+func (mgr *SessionManager) Touch(s ssh.Session) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	sess, ok := mgr.sessions[s.Context().SessionID()]
+	if !ok {
+		return
+	}
+
+	sess.LastInputAt = time.Now()
+	sess.IdleWarned = false
+}
+
+// SetCharacter records the character playing on the session, or nil when they leave the game.
+func (mgr *SessionManager) SetCharacter(s ssh.Session, c *Character) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	if sess, ok := mgr.sessions[s.Context().SessionID()]; ok {
+		sess.Character = c
+	}
+}
+
+// IdleDuration returns how long it has been since the session last sent input.
+func (mgr *SessionManager) IdleDuration(s ssh.Session) time.Duration {
+	sess := mgr.GetSession(s)
+	if sess == nil {
+		return 0
+	}
+
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return time.Since(sess.LastInputAt)
+}
+
+// CheckIdle marks idle characters as AFK, moves them to the void room and saves them, warns sessions that are
+// about to time out and disconnects those that have.
+func (mgr *SessionManager) CheckIdle() {
+	afkAfter := viper.GetDuration("server.idle_afk")
+	voidAfter := viper.GetDuration("server.idle_void")
+	timeout := viper.GetDuration("server.idle_timeout")
+	warning := viper.GetDuration("server.idle_warning")
+
+	// Characters and connections are handled after the lock is released since moving them broadcasts to
+	// their rooms and a slow client would otherwise hold up everyone's input.
+	var afk, void []*Character
+	var disconnect []*Session
+	warn := make(map[*Session]time.Duration) // Time left before each warned session is disconnected
+
+	mgr.Lock()
+	for _, sess := range mgr.sessions {
+		idle := time.Since(sess.LastInputAt)
+
+		if c := sess.Character; c != nil {
+			if voidAfter > 0 && idle >= voidAfter {
+				void = append(void, c)
+			}
+			if away, _ := c.IsAFK(); afkAfter > 0 && idle >= afkAfter && !away {
+				afk = append(afk, c)
+			}
+		}
+
+		if timeout <= 0 {
+			continue
+		}
+
+		switch {
+		case idle >= timeout:
+			slog.Info("Disconnecting idle session",
+				slog.String("session_id", sess.ID),
+				slog.String("remote_ip", sess.RemoteIP),
+				slog.Duration("idle", idle))
+
+			disconnect = append(disconnect, sess)
+		case warning > 0 && idle >= timeout-warning && !sess.IdleWarned:
+			sess.IdleWarned = true
+			warn[sess] = (timeout - idle).Round(time.Second)
+		}
+	}
+	mgr.Unlock()
+
+	for sess, left := range warn {
+		WriteStringF(sess.Conn, CRLF+"{{You have been idle for a while and will be disconnected in %s unless you enter something.}}::yellow|bold"+CRLF, left)
+	}
+
+	for _, sess := range disconnect {
+		WriteString(sess.Conn, CRLF+"{{You have been disconnected for inactivity.}}::red|bold"+CRLF)
+		sess.Conn.Close()
+	}
+
+	for _, c := range afk {
+		slog.Debug("Marking idle character AFK",
+			slog.String("character_name", c.Name))
+
+		c.SetAFK("Idle")
+		c.Send(cfmt.Sprintf(CRLF + "{{You have been marked as away from keyboard.}}::yellow" + CRLF))
+	}
+
+	if len(void) == 0 {
+		return
+	}
+
+	voidRoom := EntityMgr.GetRoom(viper.GetString("server.void_room"))
+	for _, c := range void {
+		if !c.SendToVoid(voidRoom) {
+			continue
+		}
+
+		slog.Info("Moving idle character to the void",
+			slog.String("character_name", c.Name),
+			slog.String("room_id", c.VoidRoomID))
+
+		c.Send(cfmt.Sprintf(CRLF + "{{You drift off into the void.}}::cyan" + CRLF))
+		c.Save()
+	}
+}
+
+// StartIdleChecker periodically checks for idle sessions.
+func (mgr *SessionManager) StartIdleChecker(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		mgr.CheckIdle()
+	}
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	SocialMgr = NewSocialManager()
+)
+
+type SocialManager struct {
+	sync.RWMutex
+
+	socials map[string]*Social
+}
+
+func NewSocialManager() *SocialManager {
+	return &SocialManager{
+		socials: make(map[string]*Social),
+	}
+}
+
+func (mgr *SocialManager) AddSocial(social *Social) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	slog.Debug("Adding social",
+		slog.String("social_id", social.ID))
+
+	mgr.socials[strings.ToLower(social.ID)] = social
+}
+
+func (mgr *SocialManager) GetSocial(id string) *Social {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return mgr.socials[strings.ToLower(id)]
+}
+
+// GetSocials returns all socials sorted by ID.
+func (mgr *SocialManager) GetSocials() []*Social {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	socials := make([]*Social, 0, len(mgr.socials))
+	for _, social := range mgr.socials {
+		socials = append(socials, social)
+	}
+	sort.Slice(socials, func(i, j int) bool {
+		return socials[i].ID < socials[j].ID
+	})
+
+	return socials
+}
+
+func (mgr *SocialManager) LoadDataFiles() {
+	dataFilePath := viper.GetString("data.socials_path")
+	if dataFilePath == "" {
+		dataFilePath = SocialsFilepath
+	}
+
+	slog.Info("Loading socials",
+		slog.String("datafile_path", dataFilePath))
+
+	st := time.Now()
+	files, err := os.ReadDir(dataFilePath)
+	if err != nil {
+		slog.Error("failed reading directory",
+			slog.String("datafile_path", dataFilePath),
+			slog.Any("error", err))
+	}
+
+	for _, file := range files {
+		if !IsYAMLFile(file.Name()) {
+			continue
+		}
+
+		var social Social
+		if err := LoadYAML(filepath.Join(dataFilePath, file.Name()), &social); err != nil {
+			slog.Error("failed to unmarshal social data",
+				slog.Any("error", err),
+				slog.String("file", file.Name()))
+			continue
+		}
+
+		if social.NoTarget.Actor == "" {
+			slog.Warn("Social has no untargeted messages",
+				slog.String("social_id", social.ID))
+			continue
+		}
+
+		mgr.AddSocial(&social)
+	}
+
+	slog.Info("Loaded socials",
+		slog.Duration("took", time.Since(st)),
+		slog.Int("count", len(mgr.socials)))
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"strings"
+)
+
+const (
+	SocialsFilepath = "_data/socials"
+)
+
+type (
+	// SocialMessages are the messages shown to the actor, the target and everyone else in the room.
+	SocialMessages struct {
+		Actor  string `yaml:"actor"`
+		Target string `yaml:"target"`
+		Room   string `yaml:"room"`
+	}
+
+	Social struct {
+		ID          string         `yaml:"id"`
+		Description string         `yaml:"description"`
+		NoTarget    SocialMessages `yaml:"no_target"`
+		Target      SocialMessages `yaml:"target"`
+		Self        SocialMessages `yaml:"self"`
+	}
+)
+
+// HasTarget reports whether the social can be directed at someone else.
+func (s *Social) HasTarget() bool {
+	return s.Target.Actor != ""
+}
+
+// HasSelf reports whether the social can be directed at the actor.
+func (s *Social) HasSelf() bool {
+	return s.Self.Actor != ""
+}
+
+// ActMessage substitutes the actor and target into a message and capitalizes the result. The codes are
+// lowercase for the actor and uppercase for the target:
+//   - $n/$N name
+//   - $e/$E subject pronoun (he, she, they)
+//   - $m/$M object pronoun (him, her, them)
+//   - $s/$S possessive pronoun (his, her, their)
+//   - $r/$R reflexive pronoun (himself, herself, themselves)
+//   - $v{verb}/$V{verb} the third person verb agreeing with the pronoun ("$e $v{is}" becomes "they are")
+func ActMessage(format string, actor, target *GameEntityInformation) string {
+	var builder strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '$' || i+1 >= len(format) {
+			builder.WriteByte(format[i])
+			continue
+		}
+
+		code := format[i+1]
+		entity := actor
+		if code >= 'A' && code <= 'Z' {
+			entity = target
+		}
+		if entity == nil {
+			builder.WriteByte(format[i])
+			continue
+		}
+
+		// Pronouns that start a sentence are capitalized
+		pronoun := func(p string) {
+			if startsSentence(builder.String()) {
+				p = Capitalize(p)
+			}
+			builder.WriteString(p)
+		}
+
+		switch code {
+		case 'n', 'N':
+			builder.WriteString(entity.Name)
+		case 'e', 'E':
+			pronoun(entity.SubjectPronoun())
+		case 'm', 'M':
+			pronoun(entity.ObjectPronoun())
+		case 's', 'S':
+			pronoun(entity.PossessivePronoun())
+		case 'r', 'R':
+			pronoun(entity.ReflexivePronoun())
+		case 'v', 'V':
+			end := strings.IndexByte(format[i:], '}')
+			if i+2 >= len(format) || format[i+2] != '{' || end == -1 {
+				builder.WriteByte(format[i])
+				continue
+			}
+			builder.WriteString(entity.ConjugateVerb(format[i+3 : i+end]))
+			i += end
+			continue
+		default:
+			builder.WriteByte(format[i])
+			continue
+		}
+		i++
+	}
+
+	return Capitalize(builder.String())
+}
+
+// startsSentence reports whether text written after the message so far would start a new sentence.
+func startsSentence(message string) bool {
+	message = strings.TrimRight(message, " ")
+
+	return message == "" || strings.HasSuffix(message, ".") || strings.HasSuffix(message, "!") ||
+		strings.HasSuffix(message, "?")
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActMessage(t *testing.T) {
+	alice := &GameEntityInformation{Name: "Alice", Sex: SexFemale}
+	bob := &GameEntityInformation{Name: "Bob", Sex: SexMale}
+	sam := &GameEntityInformation{Name: "Sam", Sex: SexNonBinary}
+
+	tests := []struct {
+		format   string
+		actor    *GameEntityInformation
+		target   *GameEntityInformation
+		expected string
+	}{
+		{"$n smiles at $N.", alice, bob, "Alice smiles at Bob."},
+		{"$n pats $N on $S back.", bob, alice, "Bob pats Alice on her back."},
+		{"$n pats $r on the back.", sam, sam, "Sam pats themselves on the back."},
+		{"$e $v{is} happy.", bob, nil, "He is happy."},
+		{"$e $v{is} happy.", sam, nil, "They are happy."},
+		{"$e $v{doesn't} know.", sam, nil, "They don't know."},
+		{"$n waves at $M.", alice, sam, "Alice waves at them."},
+		{"$E $V{smiles}.", alice, sam, "They smile."},
+		{"$N is missing.", alice, nil, "$N is missing."},
+		{"costs $5", alice, nil, "Costs $5"},
+		{"$e $v{is} here.", &GameEntityInformation{Name: "Old", Sex: "female"}, nil, "She is here."},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, ActMessage(tt.format, tt.actor, tt.target), tt.format)
+	}
+}
+
+func TestActMessageCapitalizesSentences(t *testing.T) {
+	viper.Set("data.socials_path", filepath.Join("..", "..", SocialsFilepath))
+	defer viper.Set("data.socials_path", nil)
+
+	mgr := NewSocialManager()
+	mgr.LoadDataFiles()
+	bob := &GameEntityInformation{Name: "Bob", Sex: SexMale}
+
+	sigh := mgr.GetSocial("sigh")
+	if assert.NotNil(t, sigh) {
+		assert.Equal(t, "Bob sighs. He is clearly not impressed.", ActMessage(sigh.NoTarget.Room, bob, nil))
+	}
+	pat := mgr.GetSocial("pat")
+	if assert.NotNil(t, pat) {
+		assert.Equal(t, "Bob pats himself on the back. He looks very pleased with himself.", ActMessage(pat.Self.Room, bob, bob))
+	}
+	assert.Equal(t, "Wow! She waves? Her hand is up.", ActMessage("wow! $e waves? $s hand is up.", &GameEntityInformation{Name: "Alice", Sex: SexFemale}, nil))
+}
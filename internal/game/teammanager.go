@@ -0,0 +1,178 @@
+package game
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	TeamMgr = NewTeamManager()
+
+	ErrNotInTeam      = errors.New("you are not in a team")
+	ErrAlreadyInTeam  = errors.New("you are already in a team")
+	ErrTargetInTeam   = errors.New("they are already in a team")
+	ErrNotTeamLeader  = errors.New("only the team leader can do that")
+	ErrNotInvited     = errors.New("you have not been invited to that team")
+	ErrNotTeamMember  = errors.New("they are not a member of your team")
+	ErrTeamInviteSelf = errors.New("you can't invite yourself")
+)
+
+type TeamManager struct {
+	sync.RWMutex
+
+	teams   map[string]*Team
+	members map[string]*Team
+}
+
+func NewTeamManager() *TeamManager {
+	return &TeamManager{
+		teams:   make(map[string]*Team),
+		members: make(map[string]*Team),
+	}
+}
+
+// GetTeam returns the team the character belongs to or nil.
+func (mgr *TeamManager) GetTeam(char *Character) *Team {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return mgr.members[strings.ToLower(char.Name)]
+}
+
+// SameTeam reports whether both characters are members of the same team.
+func (mgr *TeamManager) SameTeam(a, b *Character) bool {
+	t := mgr.GetTeam(a)
+
+	return t != nil && t == mgr.GetTeam(b)
+}
+
+// Invite invites target to the leader's team, creating the team if the leader isn't in one yet.
+func (mgr *TeamManager) Invite(leader, target *Character) (*Team, error) {
+	if strings.EqualFold(leader.Name, target.Name) {
+		return nil, ErrTeamInviteSelf
+	}
+
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	if _, ok := mgr.members[strings.ToLower(target.Name)]; ok {
+		return nil, ErrTargetInTeam
+	}
+
+	t, ok := mgr.members[strings.ToLower(leader.Name)]
+	if !ok {
+		t = NewTeam(leader)
+		mgr.teams[t.ID] = t
+		mgr.members[strings.ToLower(leader.Name)] = t
+
+		slog.Debug("Created team",
+			slog.String("team_id", t.ID),
+			slog.String("leader", leader.Name))
+	}
+
+	if !t.IsLeader(leader) {
+		return nil, ErrNotTeamLeader
+	}
+
+	t.Lock()
+	t.Invites[strings.ToLower(target.Name)] = true
+	t.Unlock()
+
+	return t, nil
+}
+
+// Accept adds the character to the team led by leader if they were invited.
+func (mgr *TeamManager) Accept(char, leader *Character) (*Team, error) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	name := strings.ToLower(char.Name)
+	if _, ok := mgr.members[name]; ok {
+		return nil, ErrAlreadyInTeam
+	}
+
+	t, ok := mgr.members[strings.ToLower(leader.Name)]
+	if !ok || !t.IsInvited(char) {
+		return nil, ErrNotInvited
+	}
+
+	t.Lock()
+	delete(t.Invites, name)
+	t.Members = append(t.Members, name)
+	t.Unlock()
+
+	mgr.members[name] = t
+
+	slog.Debug("Joined team",
+		slog.String("team_id", t.ID),
+		slog.String("character_name", char.Name))
+
+	return t, nil
+}
+
+// Leave removes the character from their team. When the leader leaves, leadership passes to the next
+// member and a team with a single member left is disbanded.
+func (mgr *TeamManager) Leave(char *Character) (*Team, error) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	name := strings.ToLower(char.Name)
+	t, ok := mgr.members[name]
+	if !ok {
+		return nil, ErrNotInTeam
+	}
+
+	mgr.removeMember(t, name)
+
+	return t, nil
+}
+
+// Kick removes target from the leader's team.
+func (mgr *TeamManager) Kick(leader, target *Character) (*Team, error) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	t, ok := mgr.members[strings.ToLower(leader.Name)]
+	if !ok {
+		return nil, ErrNotInTeam
+	}
+
+	if !t.IsLeader(leader) {
+		return nil, ErrNotTeamLeader
+	}
+
+	name := strings.ToLower(target.Name)
+	if name == t.Leader || mgr.members[name] != t {
+		return nil, ErrNotTeamMember
+	}
+
+	mgr.removeMember(t, name)
+
+	return t, nil
+}
+
+func (mgr *TeamManager) removeMember(t *Team, name string) {
+	delete(mgr.members, name)
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.Members = slices.DeleteFunc(t.Members, func(m string) bool { return m == name })
+	if t.Leader == name && len(t.Members) > 0 {
+		t.Leader = t.Members[0]
+	}
+
+	if len(t.Members) <= 1 {
+		slog.Debug("Disbanding team",
+			slog.String("team_id", t.ID))
+
+		for _, m := range t.Members {
+			delete(mgr.members, m)
+		}
+		t.Members = nil
+		delete(mgr.teams, t.ID)
+	}
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCharacter(name string) *Character {
+	c := NewCharacter()
+	c.Name = name
+
+	return c
+}
+
+func TestTeamManager(t *testing.T) {
+	mgr := NewTeamManager()
+	alice := newTestCharacter("Alice")
+	bob := newTestCharacter("Bob")
+	carol := newTestCharacter("Carol")
+
+	_, err := mgr.Invite(alice, alice)
+	assert.ErrorIs(t, err, ErrTeamInviteSelf)
+
+	_, err = mgr.Accept(bob, alice)
+	assert.ErrorIs(t, err, ErrNotInvited, "can't join without an invite")
+
+	team, err := mgr.Invite(alice, bob)
+	assert.NoError(t, err)
+	assert.True(t, team.IsLeader(alice))
+
+	_, err = mgr.Accept(bob, alice)
+	assert.NoError(t, err)
+	assert.True(t, mgr.SameTeam(alice, bob))
+	assert.False(t, mgr.SameTeam(alice, carol))
+
+	_, err = mgr.Invite(bob, carol)
+	assert.ErrorIs(t, err, ErrNotTeamLeader)
+
+	_, err = mgr.Invite(alice, carol)
+	assert.NoError(t, err)
+	_, err = mgr.Accept(carol, alice)
+	assert.NoError(t, err)
+
+	_, err = mgr.Kick(bob, carol)
+	assert.ErrorIs(t, err, ErrNotTeamLeader)
+	_, err = mgr.Kick(alice, carol)
+	assert.NoError(t, err)
+	assert.Nil(t, mgr.GetTeam(carol))
+
+	// The leader leaving passes leadership on and disbands a team of one
+	_, err = mgr.Leave(alice)
+	assert.NoError(t, err)
+	assert.Nil(t, mgr.GetTeam(alice))
+	assert.Nil(t, mgr.GetTeam(bob), "a team with one member left is disbanded")
+
+	_, err = mgr.Leave(bob)
+	assert.ErrorIs(t, err, ErrNotInTeam)
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Team is a group of characters that share a team channel and follow their leader around.
+	Team struct {
+		sync.RWMutex
+
+		ID      string
+		Leader  string
+		Members []string
+		Invites map[string]bool
+	}
+)
+
+func NewTeam(leader *Character) *Team {
+	name := strings.ToLower(leader.Name)
+
+	return &Team{
+		ID:      uuid.New().String(),
+		Leader:  name,
+		Members: []string{name},
+		Invites: make(map[string]bool),
+	}
+}
+
+func (t *Team) IsLeader(char *Character) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.Leader == strings.ToLower(char.Name)
+}
+
+func (t *Team) IsMember(char *Character) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return slices.Contains(t.Members, strings.ToLower(char.Name))
+}
+
+func (t *Team) IsInvited(char *Character) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.Invites[strings.ToLower(char.Name)]
+}
+
+// GetMembers returns the loaded characters of the team's members.
+func (t *Team) GetMembers() []*Character {
+	t.RLock()
+	defer t.RUnlock()
+
+	var members []*Character
+	for _, name := range t.Members {
+		if c := CharacterMgr.GetCharacterByName(name); c != nil {
+			members = append(members, c)
+		}
+	}
+
+	return members
+}
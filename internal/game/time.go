@@ -174,19 +174,39 @@ func handleGameTick() {
 	}
 
 	triggerTimeBasedEvents()
+	if GameTimeMgr.TickAccumulator == 0 && GameTimeMgr.CurrentMinute()%HazardInterval == 0 {
+		PulseHazards()
+	}
+	if GameTimeMgr.TickAccumulator == 0 && GameTimeMgr.CurrentMinute()%HealingInterval == 0 {
+		PulseHealing()
+	}
+	if GameTimeMgr.TickAccumulator == 0 && GameTimeMgr.CurrentMinute() == 0 {
+		EntityMgr.PulseWeather()
+	}
+	EntityMgr.PulseAreaReloads()
+	EntityMgr.PulseAreas(time.Now())
+	EntityMgr.PulseMobs()
 }
 
+// triggerTimeBasedEvents tells the characters outdoors when the sun rises and sets.
 func triggerTimeBasedEvents() {
-	hour := GameTimeMgr.CurrentHour()
+	if GameTimeMgr.CurrentMinute() != 0 || GameTimeMgr.TickAccumulator != 0 {
+		return
+	}
 
-	switch hour {
+	var msg string
+	switch GameTimeMgr.CurrentHour() {
 	case 6: // Sunrise
-		for _, c := range CharacterMgr.GetOnlineCharacters() {
-			c.Send("The sun rises over the horizon, bathing the land in light.")
-		}
+		msg = "The sun rises over the horizon, bathing the land in light."
 	case 18: // Sunset
-		for _, c := range CharacterMgr.GetOnlineCharacters() {
-			c.Send("The sun sets, and darkness envelops the world.")
+		msg = "The sun sets, and darkness envelops the world."
+	default:
+		return
+	}
+
+	for _, c := range CharacterMgr.GetOnlineCharacters() {
+		if c.Room != nil && c.Room.IsOutdoors() {
+			c.Send(msg + CRLF)
 		}
 	}
 }
@@ -2,6 +2,7 @@ package game
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -26,6 +27,15 @@ func RollChance(chance int) bool {
 	return randomNumber <= chance
 }
 
+// Capitalize upper cases the first letter of s.
+func Capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
 func Singularize(word string) string {
 	if strings.HasSuffix(word, "s") && len(word) > 1 {
 		return word[:len(word)-1] // Remove trailing 's'
@@ -83,9 +93,14 @@ func LoadJSON(filePath string, out interface{}) error {
 	return decoder.Decode(out)
 }
 
-// loadFilesFromDir is a helper that reads all files in a subdirectory of fs and applies the provided function.
-func loadFilesFromDir(fsys fs.FS, dir string, process func(data []byte)) {
+// loadFilesFromDir is a helper that reads all files in a subdirectory of fs and applies the provided function
+// to each file's name and contents.
+func loadFilesFromDir(fsys fs.FS, dir string, process func(name string, data []byte)) {
 	entries, err := fs.ReadDir(fsys, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Areas don't need to have every kind of file
+		return
+	}
 	if err != nil {
 		slog.Error("failed to read directory", "dir", dir, "error", err)
 		return
@@ -103,7 +118,7 @@ func loadFilesFromDir(fsys fs.FS, dir string, process func(data []byte)) {
 			slog.Error("failed reading file", "file", entry.Name(), "error", err)
 			continue
 		}
-		process(data)
+		process(entry.Name(), data)
 	}
 }
 
@@ -274,6 +289,7 @@ func PressEnterPrompt(s ssh.Session, label string) {
 		slog.Error("Error reading input", slog.Any("error", err))
 		s.Close()
 	}
+	SessionMgr.Touch(s)
 }
 
 func YesNoPrompt(s ssh.Session, def bool) bool {
@@ -290,6 +306,7 @@ func YesNoPrompt(s ssh.Session, def bool) bool {
 			slog.Error("Error reading input", slog.Any("error", err))
 			s.Close()
 		}
+		SessionMgr.Touch(s)
 
 		input = strings.ToLower(strings.TrimSpace(input))
 
@@ -317,10 +334,43 @@ func InputPrompt(s ssh.Session, prompt string) (string, error) {
 
 		return "", err
 	}
+	SessionMgr.Touch(s)
 
 	return strings.TrimSpace(input), nil
 }
 
+// EditorPrompt reads multiple lines of text from the session until the user saves with "/s" or aborts
+// with "/a". It reports false if the text was aborted.
+func EditorPrompt(s ssh.Session, title string) (string, bool, error) {
+	WriteStringF(s, "{{%s}}::white|bold"+CRLF, title)
+	WriteString(s, "{{Enter your text. Type /s on a line by itself to save, /a to abort, /c to clear or /p to review.}}::yellow"+CRLF)
+
+	var lines []string
+	for {
+		input, err := InputPrompt(s, "] ")
+		if err != nil {
+			return "", false, err
+		}
+
+		switch strings.ToLower(input) {
+		case "/s":
+			return strings.Join(lines, "\n"), true, nil
+		case "/a":
+			WriteString(s, "{{Aborted.}}::yellow"+CRLF)
+			return "", false, nil
+		case "/c":
+			lines = nil
+			WriteString(s, "{{Text cleared.}}::yellow"+CRLF)
+		case "/p":
+			for i, line := range lines {
+				WriteStringF(s, "{{%2d:}}::white %s"+CRLF, i+1, line)
+			}
+		default:
+			lines = append(lines, input)
+		}
+	}
+}
+
 func PasswordPrompt(s ssh.Session, prompt string) (string, error) {
 	t := term.NewTerminal(s, prompt)
 	input, err := t.ReadPassword(prompt)
@@ -330,6 +380,7 @@ func PasswordPrompt(s ssh.Session, prompt string) (string, error) {
 
 		return "", err
 	}
+	SessionMgr.Touch(s)
 
 	return strings.TrimSpace(input), nil
 }
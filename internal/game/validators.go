@@ -115,6 +115,19 @@ func CheckRequiresSpecialCharacters(pattern string) Validator {
 	}
 }
 
+// Precompile a regex for email addresses, this only checks the general shape of the address.
+var emailRegex = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// CheckEmailFormat ensures that the input looks like an email address.
+func CheckEmailFormat() Validator {
+	return func(input string) error {
+		if !emailRegex.MatchString(input) {
+			return errors.New("must be a valid email address")
+		}
+		return nil
+	}
+}
+
 // ------------------------------
 // High-Level Validation Functions
 // ------------------------------
@@ -159,3 +172,11 @@ func ValidatePassword(password string) error {
 		CheckRequiresSpecialCharacters(viper.GetString("server.special_character_regex")),
 	)
 }
+
+// ValidateEmail ensures that an email address is well formed and of a reasonable length.
+func ValidateEmail(email string) error {
+	return RunValidators(email,
+		CheckLength(3, 254),
+		CheckEmailFormat(),
+	)
+}
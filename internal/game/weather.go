@@ -0,0 +1,243 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/i582/cfmt/cmd/cfmt"
+	"golang.org/x/exp/rand"
+)
+
+const (
+	WeatherClear    = "clear" // Default
+	WeatherRain     = "rain"
+	WeatherAcidRain = "acid_rain"
+	WeatherSmog     = "smog"
+	WeatherStorm    = "storm"
+
+	WeatherChangeChance = 25 // Percent chance each game hour that an area's weather changes
+	HazardInterval      = 10 // Game minutes between rounds of environmental damage
+
+	ToxicZoneAcidDamage    = 3 // Physical damage from the toxic zone's caustic air, resisted by acid resistance
+	ToxicZoneFatigueDamage = 3 // Stun damage from breathing the toxic zone's fumes, resisted by fatigue resistance
+	AcidRainDamage         = 2 // Physical damage from acid rain outdoors, resisted by acid resistance
+)
+
+type (
+	// WeatherEffect is how a kind of weather looks and what it does to tests.
+	WeatherEffect struct {
+		Name               string
+		Description        string // Added to outdoor room descriptions
+		Color              string
+		Begins             string // Told to characters outdoors when the weather starts
+		PerceptionModifier int
+		RangedModifier     int // Includes the wind
+	}
+)
+
+// Weathers are the kinds of weather an area can have.
+var Weathers = []string{WeatherClear, WeatherRain, WeatherAcidRain, WeatherSmog, WeatherStorm}
+
+var weatherEffects = map[string]WeatherEffect{
+	WeatherClear: {
+		Name:   "Clear",
+		Color:  "white",
+		Begins: "The sky clears.",
+	},
+	WeatherRain: {
+		Name:               "Rain",
+		Description:        "Rain patters steadily on every surface.",
+		Color:              "blue",
+		Begins:             "It starts to rain.",
+		PerceptionModifier: -1,
+		RangedModifier:     -1,
+	},
+	WeatherAcidRain: {
+		Name:               "Acid rain",
+		Description:        "A thin, oily acid rain hisses where it lands, stinging any bare skin.",
+		Color:              "green",
+		Begins:             "An acrid drizzle begins to fall, stinging where it lands.",
+		PerceptionModifier: -1,
+		RangedModifier:     -1,
+	},
+	WeatherSmog: {
+		Name:               "Smog",
+		Description:        "A brown haze of smog hangs in the air, blurring everything more than a few meters away.",
+		Color:              "yellow",
+		Begins:             "A choking brown smog settles over the streets.",
+		PerceptionModifier: -3,
+		RangedModifier:     -3,
+	},
+	WeatherStorm: {
+		Name:               "Storm",
+		Description:        "Wind-driven rain lashes down as thunder rolls overhead.",
+		Color:              "magenta",
+		Begins:             "Thunder rumbles as a storm rolls in.",
+		PerceptionModifier: -3,
+		RangedModifier:     -4,
+	},
+}
+
+// GetWeatherEffect returns the effect of a kind of weather, clear weather if it's unknown.
+func GetWeatherEffect(weather string) WeatherEffect {
+	if effect, ok := weatherEffects[weather]; ok {
+		return effect
+	}
+
+	return weatherEffects[WeatherClear]
+}
+
+// CurrentWeather returns the area's weather.
+func (a *Area) CurrentWeather() string {
+	a.RLock()
+	defer a.RUnlock()
+
+	if a.weather == "" {
+		return WeatherClear
+	}
+
+	return a.weather
+}
+
+// SetWeather changes the area's weather.
+func (a *Area) SetWeather(weather string) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.weather = weather
+}
+
+// ChangeWeather picks the area's next weather from the weather it can have, where listing a kind more than
+// once makes it more likely. It returns the new weather and whether it's different from before.
+func (a *Area) ChangeWeather() (string, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	if len(a.Weather) == 0 {
+		return WeatherClear, false
+	}
+
+	prev := a.weather
+	if prev == "" {
+		prev = WeatherClear
+	}
+	a.weather = a.Weather[rand.Intn(len(a.Weather))]
+
+	return a.weather, a.weather != prev
+}
+
+// Weather returns the weather the room is exposed to. Only outdoor rooms have weather.
+func (r *Room) Weather() string {
+	if r.Area == nil || !r.IsOutdoors() {
+		return WeatherClear
+	}
+
+	return r.Area.CurrentWeather()
+}
+
+// PerceptionModifier returns the dice pool modifier for the character's perception tests in the room from
+// the light and weather.
+func (c *Character) PerceptionModifier(room *Room) int {
+	return c.VisionModifier(room) + GetWeatherEffect(room.Weather()).PerceptionModifier
+}
+
+// RangedModifier returns the dice pool modifier for the character's ranged attacks in the room from the
+// light, weather and wind.
+func (c *Character) RangedModifier(room *Room) int {
+	return c.VisionModifier(room) + GetWeatherEffect(room.Weather()).RangedModifier
+}
+
+// PulseWeather gives each area with weather a chance for it to change, telling the characters outdoors.
+func (mgr *EntityManager) PulseWeather() {
+	for _, area := range mgr.GetAllAreas() {
+		if rand.Intn(100) >= WeatherChangeChance {
+			continue
+		}
+
+		weather, changed := area.ChangeWeather()
+		if !changed {
+			continue
+		}
+
+		effect := GetWeatherEffect(weather)
+		for _, room := range mgr.GetAreaRooms(area.ID) {
+			if room.IsOutdoors() {
+				room.Broadcast(cfmt.Sprintf("{{%s}}::%s"+CRLF, effect.Begins, effect.Color), nil)
+			}
+		}
+	}
+}
+
+// PulseHazards hurts the characters in toxic zones and out in acid rain.
+func PulseHazards() {
+	for _, c := range CharacterMgr.GetOnlineCharacters() {
+		for _, msg := range c.ApplyEnvironmentalHazards() {
+			c.Send(msg)
+		}
+	}
+}
+
+// ApplyEnvironmentalHazards damages the character for the hazards in their room, each resisted by the
+// matching resistance, and returns the messages telling them what happened, including anything the damage
+// does to their condition. Characters in safe areas aren't harmed.
+func (c *Character) ApplyEnvironmentalHazards() []string {
+	room := c.Room
+	if room == nil || (room.Area != nil && room.Area.HasFlag(AreaFlagSafe)) {
+		return nil
+	}
+
+	var msgs []string
+	hazard := func(what string, dv, resistance int, stun bool) {
+		damage := max(dv-rollTest(resistance), 0)
+		if damage == 0 {
+			msgs = append(msgs, cfmt.Sprintf("{{%s, but you shrug it off.}}::yellow"+CRLF, what))
+			return
+		}
+
+		kind := "physical"
+		if stun {
+			kind = "stun"
+			c.ApplyStunDamage(damage)
+		} else {
+			c.ApplyPhysicalDamage(damage)
+		}
+		msgs = append(msgs, cfmt.Sprintf("{{%s. (%d %s)}}::red"+CRLF, what, damage, kind))
+	}
+
+	if HasAnyTag(room.AllTags(), []string{RoomTagToxicZone}) {
+		hazard("The caustic air burns your skin", ToxicZoneAcidDamage, c.GetAcidResistance(), false)
+		hazard("The toxic fumes sear your lungs", ToxicZoneFatigueDamage, c.GetFatigueResistance(), true)
+	}
+	if room.Weather() == WeatherAcidRain {
+		hazard("The acid rain stings your skin", AcidRainDamage, c.GetAcidResistance(), false)
+	}
+
+	return append(msgs, c.UpdateCondition()...)
+}
+
+// GetFormattedWeather returns the weather for the prompt, empty indoors or when it's clear.
+func GetFormattedWeather(char *Character) string {
+	if char.Room == nil {
+		return ""
+	}
+
+	weather := char.Room.Weather()
+	if weather == WeatherClear {
+		return ""
+	}
+
+	effect := GetWeatherEffect(weather)
+	return fmt.Sprintf("{{%s}}::%s ", strings.ToLower(effect.Name), effect.Color)
+}
+
+// RenderWeather describes the weather in the room, empty if there's nothing to say.
+func RenderWeather(room *Room) string {
+	weather := room.Weather()
+	if weather == WeatherClear || !slices.Contains(Weathers, weather) {
+		return ""
+	}
+
+	effect := GetWeatherEffect(weather)
+	return cfmt.Sprintf("{{%s}}::%s", effect.Description, effect.Color)
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAreaWeather(t *testing.T) {
+	area := &Area{ID: "test"}
+	assert.Equal(t, WeatherClear, area.CurrentWeather())
+
+	weather, changed := area.ChangeWeather()
+	assert.Equal(t, WeatherClear, weather, "areas without weather stay clear")
+	assert.False(t, changed)
+
+	area.Weather = []string{WeatherSmog}
+	weather, changed = area.ChangeWeather()
+	assert.Equal(t, WeatherSmog, weather)
+	assert.True(t, changed)
+	_, changed = area.ChangeWeather()
+	assert.False(t, changed)
+
+	street := &Room{ID: "street", Area: area, Tags: []string{RoomTagOutdoors}}
+	bar := &Room{ID: "bar", Area: area}
+	assert.Equal(t, WeatherSmog, street.Weather())
+	assert.Equal(t, WeatherClear, bar.Weather(), "indoor rooms have no weather")
+
+	withTestGameTime(t, 12)
+	char := newTestCharacter("Bob")
+	char.Room = street
+	assert.Equal(t, -3, char.PerceptionModifier(street))
+	assert.Equal(t, 0, char.PerceptionModifier(bar))
+
+	area.SetWeather(WeatherStorm)
+	assert.Equal(t, -4, char.RangedModifier(street))
+	assert.Contains(t, stripANSI(GetFormattedWeather(char)), "storm")
+	assert.Contains(t, stripANSI(RenderWeather(street)), "thunder")
+	assert.Empty(t, RenderWeather(bar))
+}
+
+func TestEnvironmentalHazards(t *testing.T) {
+	area := &Area{ID: "test"}
+	dump := &Room{ID: "dump", Area: area, Tags: []string{RoomTagToxicZone}}
+	char := newTestCharacter("Bob")
+	char.Body = 4
+	char.Willpower = 2
+	char.Room = dump
+
+	withTestRolls(t, 1)
+	msgs := char.ApplyEnvironmentalHazards()
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, ToxicZoneAcidDamage-1, char.PhysicalDamage)
+	assert.Equal(t, ToxicZoneFatigueDamage-1, char.StunDamage)
+
+	// Enough hits resist all of it
+	withTestRolls(t, 6)
+	char.ApplyEnvironmentalHazards()
+	assert.Equal(t, ToxicZoneAcidDamage-1, char.PhysicalDamage)
+
+	// Stun past the end of the monitor spills over onto physical
+	char.PhysicalDamage = 0
+	char.ApplyStunDamage(char.GetStunConditionMax())
+	assert.Equal(t, char.GetStunConditionMax(), char.StunDamage)
+	assert.Equal(t, 2, char.PhysicalDamage)
+
+	area.Flags = []string{AreaFlagSafe}
+	assert.Empty(t, char.ApplyEnvironmentalHazards())
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	WhoSortLevel = "level"
+	WhoSortName  = "name"
+	WhoSortIdle  = "idle"
+	WhoSortArea  = "area"
+)
+
+// WhoQuery filters and sorts the who list.
+type WhoQuery struct {
+	Name       string
+	MetatypeID string
+	Role       string
+	AreaID     string
+	AFK        bool
+	Sort       string
+}
+
+// ParseWhoQuery parses who arguments such as "metatype:ork", "area:limbo", "sort:idle", "afk", the name of
+// a permission group or the start of a character's name.
+func ParseWhoQuery(args []string) (WhoQuery, error) {
+	q := WhoQuery{Sort: WhoSortLevel}
+
+	for _, arg := range args {
+		key, value, ok := strings.Cut(strings.ToLower(arg), ":")
+		if !ok {
+			switch {
+			case key == "afk":
+				q.AFK = true
+			case PermissionMgr.GetGroup(key) != nil:
+				q.Role = key
+			default:
+				q.Name = key
+			}
+			continue
+		}
+
+		switch key {
+		case "metatype", "meta", "m":
+			q.MetatypeID = value
+		case "role", "group", "r":
+			q.Role = value
+		case "area", "a":
+			q.AreaID = value
+		case "sort", "s":
+			switch value {
+			case WhoSortLevel, WhoSortName, WhoSortIdle, WhoSortArea:
+				q.Sort = value
+			default:
+				return q, fmt.Errorf("unknown sort %q, use level, name, idle or area", value)
+			}
+		default:
+			return q, fmt.Errorf("unknown filter %q, use metatype, role, area or sort", key)
+		}
+	}
+
+	return q, nil
+}
+
+// Matches reports whether the character passes the query's filters.
+func (q WhoQuery) Matches(c *Character) bool {
+	if q.Name != "" && !strings.HasPrefix(strings.ToLower(c.Name), q.Name) {
+		return false
+	}
+	if q.MetatypeID != "" && !strings.EqualFold(c.MetatypeID, q.MetatypeID) {
+		return false
+	}
+	if q.Role != "" && !strings.EqualFold(c.Role, q.Role) {
+		return false
+	}
+	if q.AreaID != "" && (c.Room == nil || !strings.EqualFold(c.Room.AreaID, q.AreaID)) {
+		return false
+	}
+	if afk, _ := c.IsAFK(); q.AFK && !afk {
+		return false
+	}
+
+	return true
+}
+
+// SortWho sorts the characters by the query's sort order, falling back to their name.
+func (q WhoQuery) SortWho(chars []*Character, idle func(*Character) time.Duration) {
+	sort.SliceStable(chars, func(i, j int) bool {
+		a, b := chars[i], chars[j]
+		switch q.Sort {
+		case WhoSortLevel:
+			if la, lb := a.GetPermissionLevel(), b.GetPermissionLevel(); la != lb {
+				return la > lb
+			}
+		case WhoSortIdle:
+			if ia, ib := idle(a), idle(b); ia != ib {
+				return ia < ib
+			}
+		case WhoSortArea:
+			if aa, ab := whoAreaID(a), whoAreaID(b); aa != ab {
+				return aa < ab
+			}
+		}
+
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+func whoAreaID(c *Character) string {
+	if c.Room == nil {
+		return ""
+	}
+
+	return c.Room.AreaID
+}
+
+// FormatIdle renders an idle duration for the who list, empty when the character isn't idle.
+func FormatIdle(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return ""
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withTestPermissionGroups(t *testing.T) {
+	previous := PermissionMgr
+	PermissionMgr = NewPermissionManager()
+	PermissionMgr.AddGroup(&PermissionGroup{ID: "player", Level: 0})
+	PermissionMgr.AddGroup(&PermissionGroup{ID: "moderator", Level: 30})
+	PermissionMgr.AddGroup(&PermissionGroup{ID: "admin", Level: 100})
+	t.Cleanup(func() { PermissionMgr = previous })
+}
+
+func TestCharacterCanSee(t *testing.T) {
+	withTestPermissionGroups(t)
+
+	player := newTestCharacter("Player")
+	player.Role = "player"
+	mod := newTestCharacter("Mod")
+	mod.Role = "moderator"
+	admin := newTestCharacter("Admin")
+	admin.Role = "admin"
+
+	assert.True(t, player.CanSee(admin))
+
+	admin.InvisLevel = 30
+	assert.False(t, player.CanSee(admin))
+	assert.True(t, mod.CanSee(admin))
+	assert.True(t, admin.CanSee(admin))
+
+	admin.InvisLevel = 100
+	assert.False(t, mod.CanSee(admin))
+}
+
+func TestParseWhoQuery(t *testing.T) {
+	withTestPermissionGroups(t)
+
+	q, err := ParseWhoQuery([]string{"metatype:Ork", "admin", "sort:idle", "afk", "jo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ork", q.MetatypeID)
+	assert.Equal(t, "admin", q.Role)
+	assert.Equal(t, WhoSortIdle, q.Sort)
+	assert.True(t, q.AFK)
+	assert.Equal(t, "jo", q.Name)
+
+	_, err = ParseWhoQuery([]string{"sort:height"})
+	assert.Error(t, err)
+	_, err = ParseWhoQuery([]string{"colour:red"})
+	assert.Error(t, err)
+}
+
+func TestWhoQueryMatchesAndSorts(t *testing.T) {
+	withTestPermissionGroups(t)
+
+	joe := newTestCharacter("Joe")
+	joe.Role = "player"
+	joe.MetatypeID = "ork"
+	joe.Room = &Room{AreaID: "seattle"}
+	ann := newTestCharacter("Ann")
+	ann.Role = "admin"
+	ann.MetatypeID = "human"
+	ann.Room = &Room{AreaID: "limbo"}
+	bob := newTestCharacter("Bob")
+	bob.Role = "player"
+	bob.MetatypeID = "ork"
+	bob.SetAFK("")
+
+	q, _ := ParseWhoQuery([]string{"metatype:ork"})
+	assert.True(t, q.Matches(joe))
+	assert.False(t, q.Matches(ann))
+
+	q, _ = ParseWhoQuery([]string{"area:limbo"})
+	assert.True(t, q.Matches(ann))
+	assert.False(t, q.Matches(bob))
+
+	q, _ = ParseWhoQuery([]string{"afk"})
+	assert.True(t, q.Matches(bob))
+	assert.False(t, q.Matches(joe))
+
+	idle := map[*Character]time.Duration{joe: time.Minute, ann: time.Hour, bob: 0}
+	idleFunc := func(c *Character) time.Duration { return idle[c] }
+
+	list := []*Character{joe, bob, ann}
+	q, _ = ParseWhoQuery(nil)
+	q.SortWho(list, idleFunc)
+	assert.Equal(t, []*Character{ann, bob, joe}, list)
+
+	q, _ = ParseWhoQuery([]string{"sort:idle"})
+	q.SortWho(list, idleFunc)
+	assert.Equal(t, []*Character{bob, joe, ann}, list)
+
+	q, _ = ParseWhoQuery([]string{"sort:area"})
+	q.SortWho(list, idleFunc)
+	assert.Equal(t, []*Character{bob, ann, joe}, list)
+}
+
+func TestFormatIdle(t *testing.T) {
+	assert.Equal(t, "", FormatIdle(30*time.Second))
+	assert.Equal(t, "5m", FormatIdle(5*time.Minute))
+	assert.Equal(t, "2h05m", FormatIdle(125*time.Minute))
+}
@@ -0,0 +1,306 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/i582/cfmt/cmd/cfmt"
+)
+
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+)
+
+type (
+	// LintIssue is a problem found in the world data.
+	LintIssue struct {
+		Severity string
+		AreaID   string
+		Message  string
+	}
+
+	// lintWorld collects the world data read from the area files the same way the manager indexes it.
+	lintWorld struct {
+		rooms *areaIndex[*Room]
+		items *areaIndex[*ItemBlueprint]
+		mobs  *areaIndex[*MobBlueprint]
+		areas map[string][]string // area ID to directories
+	}
+)
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.AreaID, i.Message)
+}
+
+// Format renders the issue in color for players.
+func (i LintIssue) Format() string {
+	color := "red"
+	if i.Severity == LintSeverityWarning {
+		color = "yellow"
+	}
+
+	return cfmt.Sprintf("{{[%s]}}::%s {{%s:}}::cyan %s", i.Severity, color, i.AreaID, i.Message)
+}
+
+// HasLintErrors reports whether any of the issues are errors rather than warnings.
+func HasLintErrors(issues []LintIssue) bool {
+	for _, i := range issues {
+		if i.Severity == LintSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LintWorld reads every area in the areas directory and reports broken and one-way exits, unknown or
+// ambiguous references in spawns, exits, door keys and patrols, unknown metatypes, duplicate IDs, bad area
+// metadata and problems with the pregens. Metatypes, skills, qualities and pregens are checked against those
+// loaded in the manager and exit roles against the permission groups.
+func (mgr *EntityManager) LintWorld(areasFS fs.FS) []LintIssue {
+	var issues []LintIssue
+	add := func(severity, areaID, format string, args ...any) {
+		issues = append(issues, LintIssue{Severity: severity, AreaID: areaID, Message: fmt.Sprintf(format, args...)})
+	}
+
+	w := &lintWorld{
+		rooms: newAreaIndex[*Room](),
+		items: newAreaIndex[*ItemBlueprint](),
+		mobs:  newAreaIndex[*MobBlueprint](),
+		areas: make(map[string][]string),
+	}
+
+	areaDirs, err := fs.ReadDir(areasFS, ".")
+	if err != nil {
+		add(LintSeverityError, "-", "unable to read the areas directory: %v", err)
+		return issues
+	}
+
+	for _, d := range areaDirs {
+		if !d.IsDir() {
+			continue
+		}
+
+		areaFS, err := fs.Sub(areasFS, d.Name())
+		if err != nil {
+			add(LintSeverityError, d.Name(), "unable to read the area: %v", err)
+			continue
+		}
+
+		data, err := mgr.readArea(areaFS, d.Name())
+		if err != nil {
+			add(LintSeverityError, d.Name(), "%v", err)
+			continue
+		}
+		for _, err := range data.Errors {
+			add(LintSeverityError, data.Area.ID, "%v", err)
+		}
+
+		w.areas[strings.ToLower(data.Area.ID)] = append(w.areas[strings.ToLower(data.Area.ID)], d.Name())
+		for _, flag := range data.Area.Flags {
+			if !slices.Contains(AreaFlags, strings.ToLower(flag)) {
+				add(LintSeverityError, data.Area.ID, "area has the unknown flag %q", flag)
+			}
+		}
+		if data.Area.ThreatLevel != "" && !slices.Contains(ThreatLevels, strings.ToLower(data.Area.ThreatLevel)) {
+			add(LintSeverityError, data.Area.ID, "area has the unknown threat level %q", data.Area.ThreatLevel)
+		}
+		if data.Area.MaxKarma != 0 && data.Area.MinKarma > data.Area.MaxKarma {
+			add(LintSeverityError, data.Area.ID, "area min_karma %d is above max_karma %d", data.Area.MinKarma, data.Area.MaxKarma)
+		}
+		if len(data.Area.AmbientMessages) > 0 && data.Area.AmbientInterval <= 0 {
+			add(LintSeverityWarning, data.Area.ID, "area has ambient messages but no ambient_interval, so they are never sent")
+		}
+		for _, weather := range data.Area.Weather {
+			if !slices.Contains(Weathers, weather) {
+				add(LintSeverityError, data.Area.ID, "area has the unknown weather %q", weather)
+			}
+		}
+		for _, r := range data.Rooms {
+			if !w.rooms.add(r.AreaID, r.ID, r) {
+				add(LintSeverityError, r.AreaID, "room %q is defined more than once (%s)", r.ID, r.File)
+			}
+		}
+		for _, i := range data.Items {
+			if !w.items.add(i.AreaID, i.ID, i) {
+				add(LintSeverityError, i.AreaID, "item %q is defined more than once (%s)", i.ID, i.File)
+			}
+		}
+		for _, m := range data.Mobs {
+			if !w.mobs.add(m.AreaID, m.ID, m) {
+				add(LintSeverityError, m.AreaID, "mob %q is defined more than once (%s)", m.ID, m.File)
+			}
+		}
+	}
+
+	// Duplicate IDs, areas can reuse IDs but bare references to them have to be qualified
+	for id, dirs := range w.areas {
+		if len(dirs) > 1 {
+			add(LintSeverityError, id, "area ID is used by the directories %s", strings.Join(dirs, ", "))
+		}
+	}
+	for id, rooms := range w.rooms.bare {
+		if len(rooms) > 1 {
+			add(LintSeverityWarning, "-", "room %q is defined by more than one area (%s)", id, strings.Join(rooms, ", "))
+		}
+	}
+	for id, items := range w.items.bare {
+		if len(items) > 1 {
+			add(LintSeverityWarning, "-", "item %q is defined by more than one area (%s)", id, strings.Join(items, ", "))
+		}
+	}
+	for id, mobs := range w.mobs.bare {
+		if len(mobs) > 1 {
+			add(LintSeverityWarning, "-", "mob %q is defined by more than one area (%s)", id, strings.Join(mobs, ", "))
+		}
+	}
+
+	// Rooms
+	for _, r := range w.rooms.all() {
+		if r.Light != "" && !slices.Contains(LightLevels, r.Light) {
+			add(LintSeverityError, r.AreaID, "room %q has the unknown light level %q", r.ID, r.Light)
+		}
+
+		for dir, exit := range r.Exits {
+			if ParseDirection(dir) == "" {
+				add(LintSeverityError, r.AreaID, "room %q has an exit in the unknown direction %q", r.ID, dir)
+				continue
+			}
+
+			if exit.MinRole != "" && PermissionMgr.GetGroup(exit.MinRole) == nil {
+				add(LintSeverityError, r.AreaID, "room %q exit %s is restricted to the unknown role %q", r.ID, dir, exit.MinRole)
+			}
+			if exit.Type != "" && !slices.Contains(ExitTypes, exit.Type) {
+				add(LintSeverityError, r.AreaID, "room %q exit %s has the unknown type %q", r.ID, dir, exit.Type)
+			}
+			if exit.MaxSize != "" && !slices.Contains(MetatypeSizes, exit.MaxSize) {
+				add(LintSeverityError, r.AreaID, "room %q exit %s is limited to the unknown size %q", r.ID, dir, exit.MaxSize)
+			}
+
+			target, err := w.rooms.lookup(r.AreaID, exit.RoomID)
+			if err != nil {
+				add(LintSeverityError, r.AreaID, "room %q exit %s leads to %s", r.ID, dir, lintReference("room", exit.RoomID, err))
+				continue
+			}
+
+			back := target.Exits[ReverseDirection(dir)]
+			var backRoom *Room
+			if back != nil {
+				backRoom, _ = w.rooms.lookup(target.AreaID, back.RoomID)
+			}
+			switch {
+			case exit.OneWay:
+				// Meant to have no way back
+			case back == nil && exit.Door != nil:
+				add(LintSeverityError, r.AreaID, "room %q exit %s has a door but %q has no exit %s back", r.ID, dir, exit.RoomID, ReverseDirection(dir))
+			case back == nil:
+				add(LintSeverityWarning, r.AreaID, "room %q exit %s is one-way, %q has no exit %s back", r.ID, dir, exit.RoomID, ReverseDirection(dir))
+			case backRoom != r:
+				add(LintSeverityWarning, r.AreaID, "room %q exit %s is one-way, %q exit %s leads to %q", r.ID, dir, exit.RoomID, ReverseDirection(dir), back.RoomID)
+			}
+
+			if exit.Door != nil {
+				for _, key := range exit.Door.KeyIDs {
+					if _, err := w.items.lookup(r.AreaID, key); err != nil {
+						add(LintSeverityError, r.AreaID, "room %q door %s uses %s", r.ID, dir, lintReference("key", key, err))
+					}
+				}
+			}
+		}
+
+		for _, spawn := range r.Spawns {
+			switch {
+			case spawn.ItemID != "":
+				if _, err := w.items.lookup(r.AreaID, spawn.ItemID); err != nil {
+					add(LintSeverityError, r.AreaID, "room %q spawns %s", r.ID, lintReference("item", spawn.ItemID, err))
+				}
+			case spawn.MobID != "":
+				if _, err := w.mobs.lookup(r.AreaID, spawn.MobID); err != nil {
+					add(LintSeverityError, r.AreaID, "room %q spawns %s", r.ID, lintReference("mob", spawn.MobID, err))
+				}
+			default:
+				add(LintSeverityError, r.AreaID, "room %q has a spawn without an item or mob", r.ID)
+			}
+		}
+	}
+
+	// Mobs
+	for _, m := range w.mobs.all() {
+		if err := m.GameEntityInformation.Validate(); err != nil {
+			add(LintSeverityError, m.AreaID, "mob %q: %s", m.ID, lintJoin(err))
+		}
+		if m.MetatypeID != "" && m.Metatype == nil {
+			add(LintSeverityError, m.AreaID, "mob %q uses the unknown metatype %q", m.ID, m.MetatypeID)
+		}
+		for _, spawn := range m.Spawns {
+			if _, err := w.items.lookup(m.AreaID, spawn.ItemID); err != nil {
+				add(LintSeverityError, m.AreaID, "mob %q spawns %s", m.ID, lintReference("item", spawn.ItemID, err))
+			}
+		}
+		for _, ref := range m.Patrol {
+			if _, err := w.rooms.lookup(m.AreaID, ref); err != nil {
+				add(LintSeverityError, m.AreaID, "mob %q patrols %s", m.ID, lintReference("room", ref, err))
+			}
+		}
+	}
+
+	// Pregens
+	for _, p := range mgr.GetPregens() {
+		if err := p.GameEntityInformation.Validate(); err != nil {
+			add(LintSeverityError, "pregens", "pregen %q: %s", p.ID, lintJoin(err))
+		}
+		if err := p.GameEntityDynamic.Validate(); err != nil {
+			add(LintSeverityError, "pregens", "pregen %q: %s", p.ID, lintJoin(err))
+		}
+		if p.MetatypeID != "" && mgr.GetMetatype(p.MetatypeID) == nil {
+			add(LintSeverityError, "pregens", "pregen %q uses the unknown metatype %q", p.ID, p.MetatypeID)
+		}
+		for id, skill := range p.Skills {
+			if skill != nil && skill.BlueprintID != "" {
+				id = skill.BlueprintID
+			}
+			if mgr.GetSkillBlueprint(id) == nil {
+				add(LintSeverityError, "pregens", "pregen %q has the unknown skill %q", p.ID, id)
+			}
+		}
+		for id, quality := range p.Qualtities {
+			if quality != nil && quality.BlueprintID != "" {
+				id = quality.BlueprintID
+			}
+			if mgr.GetQualityBlueprint(id) == nil {
+				add(LintSeverityError, "pregens", "pregen %q has the unknown quality %q", p.ID, id)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].AreaID != issues[j].AreaID {
+			return issues[i].AreaID < issues[j].AreaID
+		}
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity == LintSeverityError
+		}
+		return issues[i].Message < issues[j].Message
+	})
+
+	return issues
+}
+
+// lintReference describes a reference that couldn't be resolved.
+func lintReference(kind, ref string, err error) string {
+	if errors.Is(err, ErrReferenceAmbiguous) {
+		return fmt.Sprintf("the ambiguous %s %q, which is %v", kind, ref, err)
+	}
+
+	return fmt.Sprintf("the unknown %s %q", kind, ref)
+}
+
+// lintJoin puts the errors joined by errors.Join on one line.
+func lintJoin(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", "; ")
+}
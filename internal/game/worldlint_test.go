@@ -0,0 +1,110 @@
+package game
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lintMessages(issues []LintIssue) string {
+	var lines []string
+	for _, i := range issues {
+		lines = append(lines, i.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func TestLintWorld(t *testing.T) {
+	dir := t.TempDir()
+	writeTestAreaFile(t, dir, "one/manifest.yml", "id: one\n")
+	writeTestAreaFile(t, dir, "one/rooms/hall.yml", `id: hall
+exits:
+  north:
+    room_id: study
+  east:
+    room_id: nowhere
+  west:
+    room_id: closet
+    door:
+      key_ids: [missing_key]
+spawns:
+  - item_id: lamp
+  - mob_id: ghost
+`)
+	writeTestAreaFile(t, dir, "one/rooms/study.yml", "id: study\nexits:\n  south:\n    room_id: hall\n")
+	writeTestAreaFile(t, dir, "one/rooms/closet.yml", "id: closet\n")
+	writeTestAreaFile(t, dir, "one/mobs/butler.yml", "id: butler\nmetatype_id: dwarf\nspawns:\n  - item_id: tray\npatrol: [hall, attic]\n")
+	writeTestAreaFile(t, dir, "one/items/broken.yml", "id: [broken\n")
+	writeTestAreaFile(t, dir, "two/manifest.yml", "id: two\n")
+	writeTestAreaFile(t, dir, "two/rooms/study.yml", "id: study\nexits:\n  east:\n    room_id: one:hall\n")
+	writeTestAreaFile(t, dir, "two/rooms/study_copy.yml", "id: study\n")
+	writeTestAreaFile(t, dir, "three/manifest.yml", "id: three\n")
+	writeTestAreaFile(t, dir, "three/rooms/porch.yml", "id: porch\nexits:\n  north:\n    room_id: study\n")
+
+	mgr := NewEntityManager()
+	mgr.AddMetatype(&Metatype{ID: "human"})
+	mgr.AddSkillBlueprint(&SkillBlueprint{ID: "blades"})
+	pregen := NewPregen()
+	pregen.ID = "samurai"
+	pregen.MetatypeID = "human"
+	pregen.Skills["blades"] = &Skill{BlueprintID: "blades", Rating: 3}
+	pregen.Skills["hacking"] = &Skill{BlueprintID: "hacking", Rating: 1}
+	mgr.AddPregen(pregen)
+
+	issues := mgr.LintWorld(os.DirFS(dir))
+	messages := lintMessages(issues)
+
+	assert.True(t, HasLintErrors(issues))
+	assert.Contains(t, messages, `room "hall" exit east leads to the unknown room "nowhere"`)
+	assert.Contains(t, messages, `[error] one: room "hall" exit west has a door but "closet" has no exit east back`)
+	assert.Contains(t, messages, `room "hall" door west uses the unknown key "missing_key"`)
+	assert.Contains(t, messages, `room "hall" spawns the unknown item "lamp"`)
+	assert.Contains(t, messages, `room "hall" spawns the unknown mob "ghost"`)
+	assert.Contains(t, messages, `mob "butler" uses the unknown metatype "dwarf"`)
+	assert.Contains(t, messages, `mob "butler" spawns the unknown item "tray"`)
+	assert.Contains(t, messages, `mob "butler" patrols the unknown room "attic"`)
+	assert.NotContains(t, messages, `patrols the unknown room "hall"`)
+	assert.Contains(t, messages, `[error] one: failed to unmarshal item file items/broken.yml`)
+	assert.Contains(t, messages, `[warning] -: room "study" is defined by more than one area (one:study, two:study)`)
+	assert.Contains(t, messages, `[error] two: room "study" is defined more than once (study_copy.yml)`)
+	assert.Contains(t, messages, `[error] three: room "porch" exit north leads to the ambiguous room "study"`)
+	assert.NotContains(t, messages, `room "study" exit east leads to`)
+	assert.Contains(t, messages, `pregen "samurai" has the unknown skill "hacking"`)
+	assert.NotContains(t, messages, `"blades"`)
+}
+
+func TestLintWorldClean(t *testing.T) {
+	dir := t.TempDir()
+	writeTestAreaFile(t, dir, "one/manifest.yml", "id: one\n")
+	writeTestAreaFile(t, dir, "one/rooms/hall.yml", "id: hall\nexits:\n  north:\n    room_id: study\n")
+	writeTestAreaFile(t, dir, "one/rooms/study.yml", "id: study\n")
+
+	issues := NewEntityManager().LintWorld(os.DirFS(dir))
+
+	assert.False(t, HasLintErrors(issues), "one-way exits without doors are only warnings")
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, LintSeverityWarning, issues[0].Severity)
+	}
+}
+
+func TestGameEntityValidate(t *testing.T) {
+	info := &GameEntityInformation{ID: "bob", MetatypeID: "human", Sex: "male", GeneralDisposition: DispositionFriendly}
+	assert.NoError(t, info.Validate())
+
+	info = &GameEntityInformation{Sex: "robot", GeneralDisposition: "grumpy"}
+	err := info.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "id is required")
+		assert.Contains(t, err.Error(), `unknown sex "robot"`)
+		assert.Contains(t, err.Error(), `unknown general_disposition "grumpy"`)
+	}
+
+	dynamic := NewGameEntityDynamic()
+	assert.NoError(t, dynamic.Validate())
+	dynamic.Edge = -1
+	dynamic.Skills["blades"] = &Skill{Rating: -2}
+	assert.Error(t, dynamic.Validate())
+}